@@ -0,0 +1,379 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package agent serves component credentials to local processes over a Unix
+// domain socket. The agent holds the only CompCredStore session, so scripts
+// on a management node can read credentials without handling Vault tokens.
+// Callers are identified by the kernel (SO_PEERCRED), with their
+// supplementary groups read from /proc, and authorised by uid, gid and xname
+// rules, and lookups are cached for a short time.
+//
+// The protocol is one JSON object per line in each direction:
+//
+//	-> {"xname":"x1000c0s1b0"}
+//	<- {"credentials":{"xname":"x1000c0s1b0","username":"root",...}}
+//	<- {"error":"permission denied"}
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// How long credentials are cached when Config.CacheTTL is not set.
+const DefaultCacheTTL = time.Minute
+
+// The mode of the socket when Config.SocketMode is not set. Access is
+// controlled by Rules, not by the socket's permissions.
+const DefaultSocketMode os.FileMode = 0666
+
+// Peer identifies the process on the other end of a connection.
+type Peer struct {
+	PID int32
+	UID uint32
+	GID uint32
+
+	// Supplementary groups. Empty if they could not be read, which only
+	// ever denies access, as rules only allow it.
+	Groups []uint32
+}
+
+// Rule allows matching callers to read the credentials of matching xnames.
+// A rule with no UIDs matches any uid, and likewise for GIDs, which match a
+// caller's primary or supplementary groups; a rule with no Xnames allows
+// nothing.
+type Rule struct {
+	UIDs   []uint32 `json:"uids,omitempty"`
+	GIDs   []uint32 `json:"gids,omitempty"`
+	Xnames []string `json:"xnames"` // path.Match patterns, e.g. "x1000c*b0"
+}
+
+func (r Rule) allows(peer Peer, xname string) bool {
+	if len(r.UIDs) > 0 && !containsID(r.UIDs, peer.UID) {
+		return false
+	}
+	if len(r.GIDs) > 0 && !containsID(r.GIDs, peer.GID) && !containsAnyID(r.GIDs, peer.Groups) {
+		return false
+	}
+	for _, pattern := range r.Xnames {
+		if ok, _ := path.Match(pattern, xname); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []uint32, id uint32) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyID(ids []uint32, others []uint32) bool {
+	for _, id := range others {
+		if containsID(ids, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the supplementary groups from the contents of a /proc/<pid>/status
+// file, checking that its real uid is uid in case the pid was reused.
+func parseStatusGroups(status string, uid uint32) ([]uint32, error) {
+	var (
+		groups  []uint32
+		uidSeen bool
+	)
+	for _, line := range strings.Split(status, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		switch name {
+		case "Uid":
+			if len(fields) == 0 || fields[0] != strconv.FormatUint(uint64(uid), 10) {
+				return nil, fmt.Errorf("process uid does not match the peer's")
+			}
+			uidSeen = true
+		case "Groups":
+			for _, f := range fields {
+				gid, err := strconv.ParseUint(f, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid group %q", f)
+				}
+				groups = append(groups, uint32(gid))
+			}
+		}
+	}
+	if !uidSeen {
+		return nil, fmt.Errorf("no Uid in process status")
+	}
+	return groups, nil
+}
+
+// Config holds the settings for an Agent.
+type Config struct {
+	Store      *cc.CompCredStore
+	SocketPath string
+	SocketMode os.FileMode
+	Rules      []Rule
+	CacheTTL   time.Duration
+}
+
+type cacheEntry struct {
+	cred    cc.CompCredentials
+	expires time.Time
+}
+
+// Agent serves credential lookups over a Unix socket.
+type Agent struct {
+	cfg Config
+
+	mu       sync.Mutex
+	cache    map[string]cacheEntry
+	listener net.Listener
+	conns    map[net.Conn]bool
+	closed   bool
+	wg       sync.WaitGroup
+
+	// Replaced in tests.
+	now      func() time.Time
+	peerCred func(conn *net.UnixConn) (Peer, error)
+}
+
+// Create a new Agent.
+func NewAgent(cfg Config) *Agent {
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	if cfg.SocketMode == 0 {
+		cfg.SocketMode = DefaultSocketMode
+	}
+	return &Agent{
+		cfg:      cfg,
+		cache:    make(map[string]cacheEntry),
+		conns:    make(map[net.Conn]bool),
+		now:      time.Now,
+		peerCred: peerCred,
+	}
+}
+
+// Create the socket at Config.SocketPath, replacing a stale one, and serve
+// requests until Close is called.
+func (a *Agent) ListenAndServe() error {
+	if fi, err := os.Lstat(a.cfg.SocketPath); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s exists and is not a socket", a.cfg.SocketPath)
+		}
+		os.Remove(a.cfg.SocketPath)
+	}
+
+	l, err := net.Listen("unix", a.cfg.SocketPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(a.cfg.SocketPath, a.cfg.SocketMode); err != nil {
+		l.Close()
+		return err
+	}
+
+	return a.Serve(l)
+}
+
+// Serve requests on a Unix socket listener until Close is called.
+func (a *Agent) Serve(l net.Listener) error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		l.Close()
+		return net.ErrClosed
+	}
+	a.listener = l
+	a.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			a.mu.Lock()
+			closed := a.closed
+			a.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		uconn, ok := conn.(*net.UnixConn)
+		if !ok {
+			conn.Close()
+			continue
+		}
+
+		a.mu.Lock()
+		a.conns[conn] = true
+		a.mu.Unlock()
+		a.wg.Add(1)
+		go a.handle(uconn)
+	}
+}
+
+// Stop serving, close open connections and drop the cache.
+func (a *Agent) Close() error {
+	a.mu.Lock()
+	a.closed = true
+	var err error
+	if a.listener != nil {
+		err = a.listener.Close()
+	}
+	for conn := range a.conns {
+		conn.Close()
+	}
+	a.cache = make(map[string]cacheEntry)
+	a.mu.Unlock()
+
+	a.wg.Wait()
+	return err
+}
+
+type request struct {
+	Xname string `json:"xname"`
+}
+
+type response struct {
+	Credentials *cc.CompCredentials `json:"credentials,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+var errDenied = errors.New("permission denied")
+
+func (a *Agent) handle(conn *net.UnixConn) {
+	defer a.wg.Done()
+	defer func() {
+		conn.Close()
+		a.mu.Lock()
+		delete(a.conns, conn)
+		a.mu.Unlock()
+	}()
+
+	peer, err := a.peerCred(conn)
+	if err != nil {
+		log.WithError(err).Error("Unable to identify agent client")
+		json.NewEncoder(conn).Encode(response{Error: errDenied.Error()})
+		return
+	}
+
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req request
+		var resp response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = "invalid request"
+		} else if cred, err := a.lookup(peer, req.Xname); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Credentials = &cred
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Authorise a request and return the credentials for it.
+func (a *Agent) lookup(peer Peer, xname string) (cc.CompCredentials, error) {
+	allowed := false
+	for _, rule := range a.cfg.Rules {
+		if rule.allows(peer, xname) {
+			allowed = true
+			break
+		}
+	}
+	logger := log.WithFields(log.Fields{"pid": peer.PID, "uid": peer.UID, "gid": peer.GID, "xname": xname})
+	if !allowed {
+		logger.Warn("Denied agent credential request")
+		return cc.CompCredentials{}, errDenied
+	}
+
+	a.mu.Lock()
+	entry, ok := a.cache[xname]
+	a.mu.Unlock()
+	if ok && a.now().Before(entry.expires) {
+		return entry.cred, nil
+	}
+
+	cred, err := a.cfg.Store.GetCompCred(xname)
+	if err != nil {
+		logger.WithError(err).Error("Unable to read credentials for agent client")
+		return cc.CompCredentials{}, fmt.Errorf("unable to read credentials")
+	}
+	if cred.Xname == "" {
+		return cc.CompCredentials{}, fmt.Errorf("no credentials for %s", xname)
+	}
+
+	a.mu.Lock()
+	a.cache[xname] = cacheEntry{cred: cred, expires: a.now().Add(a.cfg.CacheTTL)}
+	a.mu.Unlock()
+	return cred, nil
+}
+
+// Get the credentials for a component from the agent listening at socketPath.
+func Get(socketPath, xname string) (cc.CompCredentials, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return cc.CompCredentials{}, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(request{Xname: xname}); err != nil {
+		return cc.CompCredentials{}, err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return cc.CompCredentials{}, err
+	}
+	if resp.Error != "" {
+		return cc.CompCredentials{}, errors.New(resp.Error)
+	}
+	if resp.Credentials == nil {
+		return cc.CompCredentials{}, fmt.Errorf("empty response from agent")
+	}
+	return *resp.Credentials, nil
+}
@@ -0,0 +1,226 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package agent
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func startAgent(t *testing.T, rules []Rule) (string, *cc.CompCredStore, *testClock) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only supported on Linux")
+	}
+
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", Username: "root", Password: "123"})
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x3000c0s1b0", Username: "root", Password: "456"})
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	clock := &testClock{now: time.Now()}
+	a := NewAgent(Config{
+		Store:      ccs,
+		SocketPath: socketPath,
+		Rules:      rules,
+		CacheTTL:   time.Minute,
+	})
+	a.now = clock.Now
+
+	done := make(chan error, 1)
+	go func() { done <- a.ListenAndServe() }()
+	t.Cleanup(func() {
+		a.Close()
+		if err := <-done; err != nil {
+			t.Errorf("ListenAndServe returned %v", err)
+		}
+	})
+
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(socketPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return socketPath, ccs, clock
+}
+
+func TestRules(t *testing.T) {
+	uid := uint32(os.Getuid())
+	gid := uint32(os.Getgid())
+
+	var tests = []struct {
+		rules   []Rule
+		xname   string
+		respErr bool
+	}{
+		{[]Rule{{UIDs: []uint32{uid}, Xnames: []string{"*"}}}, "x1000c0s1b0", false},
+		{[]Rule{{GIDs: []uint32{gid}, Xnames: []string{"x1000c*"}}}, "x1000c0s1b0", false},
+		{[]Rule{{GIDs: []uint32{gid}, Xnames: []string{"x1000c*"}}}, "x3000c0s1b0", true},
+		{[]Rule{{UIDs: []uint32{uid + 1}, Xnames: []string{"*"}}}, "x1000c0s1b0", true},
+		{[]Rule{{UIDs: []uint32{uid}}}, "x1000c0s1b0", true},
+		{[]Rule{{Xnames: []string{"*"}}}, "x9999c0s1b0", true},
+		{nil, "x1000c0s1b0", true},
+	}
+
+	for i, test := range tests {
+		t.Run("", func(t *testing.T) {
+			socketPath, _, _ := startAgent(t, test.rules)
+			cred, err := Get(socketPath, test.xname)
+			if (err != nil) != test.respErr {
+				t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+			}
+			if err == nil && cred.Xname != test.xname {
+				t.Errorf("Test %v Failed: Expected credentials for %v but got %v", i, test.xname, cred.Xname)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	socketPath, ccs, clock := startAgent(t, []Rule{{Xnames: []string{"*"}}})
+
+	cred, err := Get(socketPath, "x1000c0s1b0")
+	if err != nil || cred.Password != "123" {
+		t.Fatalf("Expected the stored password but got %v", err)
+	}
+
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", Username: "root", Password: "789"})
+	cred, err = Get(socketPath, "x1000c0s1b0")
+	if err != nil || cred.Password != "123" {
+		t.Errorf("Expected the cached password before the TTL expires")
+	}
+
+	clock.Advance(2 * time.Minute)
+	cred, err = Get(socketPath, "x1000c0s1b0")
+	if err != nil || cred.Password != "789" {
+		t.Errorf("Expected the new password after the TTL expires")
+	}
+}
+
+func TestPeerCred(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only supported on Linux")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "peer.sock")
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := net.Dial("unix", socketPath)
+		if err == nil {
+			defer conn.Close()
+			conn.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("Unable to accept: %v", err)
+	}
+	defer conn.Close()
+
+	peer, err := peerCred(conn.(*net.UnixConn))
+	if err != nil {
+		t.Fatalf("peerCred failed: %v", err)
+	}
+	expected := Peer{PID: int32(os.Getpid()), UID: uint32(os.Getuid()), GID: uint32(os.Getgid())}
+	groups, _ := os.Getgroups()
+	for _, gid := range groups {
+		expected.Groups = append(expected.Groups, uint32(gid))
+	}
+	if !reflect.DeepEqual(peer, expected) {
+		t.Errorf("Expected peer %+v but got %+v", expected, peer)
+	}
+}
+
+func TestRuleGroups(t *testing.T) {
+	peer := Peer{UID: 1000, GID: 1000, Groups: []uint32{27, 1500}}
+
+	var tests = []struct {
+		rule    Rule
+		allowed bool
+	}{
+		{Rule{GIDs: []uint32{1000}, Xnames: []string{"*"}}, true},
+		{Rule{GIDs: []uint32{1500}, Xnames: []string{"*"}}, true},
+		{Rule{GIDs: []uint32{1501}, Xnames: []string{"*"}}, false},
+		{Rule{UIDs: []uint32{1001}, GIDs: []uint32{1500}, Xnames: []string{"*"}}, false},
+	}
+	for i, test := range tests {
+		if allowed := test.rule.allows(peer, "x1000c0s1b0"); allowed != test.allowed {
+			t.Errorf("Test %v Failed: Expected allowed %v but got %v", i, test.allowed, allowed)
+		}
+	}
+}
+
+func TestParseStatusGroups(t *testing.T) {
+	var tests = []struct {
+		status  string
+		groups  []uint32
+		respErr bool
+	}{
+		{"Name:\tbash\nUid:\t1000\t1000\t1000\t1000\nGid:\t1000\t1000\t1000\t1000\nGroups:\t27 1500 \n", []uint32{27, 1500}, false},
+		{"Uid:\t1000\t1000\t1000\t1000\nGroups:\t\n", nil, false},
+		// The pid was reused by another user's process.
+		{"Uid:\t0\t0\t0\t0\nGroups:\t0 1500\n", nil, true},
+		{"Groups:\t1500\n", nil, true},
+		{"Uid:\t1000\t1000\t1000\t1000\nGroups:\twheel\n", nil, true},
+	}
+	for i, test := range tests {
+		groups, err := parseStatusGroups(test.status, 1000)
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+		}
+		if !reflect.DeepEqual(groups, test.groups) {
+			t.Errorf("Test %v Failed: Expected groups %v but got %v", i, test.groups, groups)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// Get the credentials of the process on the other end of a Unix socket.
+func peerCred(conn *net.UnixConn) (Peer, error) {
+	var (
+		ucred *unix.Ucred
+		serr  error
+	)
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return Peer{}, err
+	}
+	err = raw.Control(func(fd uintptr) {
+		ucred, serr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return Peer{}, err
+	}
+	if serr != nil {
+		return Peer{}, fmt.Errorf("unable to get peer credentials: %v", serr)
+	}
+
+	peer := Peer{PID: ucred.Pid, UID: ucred.Uid, GID: ucred.Gid}
+	// SO_PEERCRED only has the primary group. Without the others the
+	// caller is matched on it alone.
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", ucred.Pid))
+	if err == nil {
+		peer.Groups, err = parseStatusGroups(string(status), ucred.Uid)
+	}
+	if err != nil {
+		log.WithError(err).WithField("pid", ucred.Pid).Warn("Unable to read peer's supplementary groups")
+	}
+	return peer, nil
+}
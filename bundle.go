@@ -0,0 +1,432 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// The format name and the newest format version of the bundles Export
+// writes. Import rejects bundles with a newer version.
+const (
+	BundleFormat  = "hms-compcredentials-bundle"
+	BundleVersion = 1
+)
+
+// scrypt parameters for new passphrase bundles, and the most work Import
+// will accept from a bundle's header.
+const (
+	scryptN    = 1 << 15
+	scryptR    = 8
+	scryptP    = 1
+	maxScryptN = 1 << 20
+)
+
+// The context X25519 bundle keys are derived with.
+const bundleKeyInfo = "hms-compcredentials bundle key"
+
+// ConflictPolicy says what Import does with credentials that are already
+// stored and differ from the bundle's.
+type ConflictPolicy string
+
+const (
+	// Keep the stored credentials.
+	ConflictSkip ConflictPolicy = "skip"
+	// Replace the stored credentials with the bundle's.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// Import nothing if any credentials conflict.
+	ConflictFail ConflictPolicy = "fail"
+)
+
+// BundleKey is what a bundle is encrypted with: a passphrase, an X25519 key
+// pair, of which Export needs only the public key and Import only the
+// private key, or a key split into shares by SplitBundleKey.
+type BundleKey struct {
+	Passphrase string
+	PublicKey  *ecdh.PublicKey
+	PrivateKey *ecdh.PrivateKey
+
+	// A 256-bit key from SplitBundleKey or CombineBundleShares.
+	Key []byte
+
+	// How many shares Key was split into are needed to rebuild it.
+	threshold int
+}
+
+// ImportOptions control Import.
+type ImportOptions struct {
+	// Defaults to ConflictSkip.
+	Conflict ConflictPolicy
+
+	// Report the changes without storing anything.
+	DryRun bool
+}
+
+// ImportChange describes what Import did, or would do, with one set of
+// credentials. Change is one of "created", "unchanged", "skipped",
+// "overwritten" or "conflict"; Fields lists the JSON names of the fields
+// that differ from the stored credentials, never their values.
+type ImportChange struct {
+	Xname  string   `json:"xname"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// The unencrypted part of a bundle. All of it is authenticated along with
+// the ciphertext, so it cannot be altered without Import noticing.
+type bundleHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Created string `json:"created"`
+
+	// "scrypt" for passphrase bundles, "x25519" for public key ones and
+	// "shamir" for ones whose key is split into shares.
+	KDF          string `json:"kdf"`
+	Salt         []byte `json:"salt,omitempty"`
+	ScryptN      int    `json:"scrypt_n,omitempty"`
+	ScryptR      int    `json:"scrypt_r,omitempty"`
+	ScryptP      int    `json:"scrypt_p,omitempty"`
+	EphemeralKey []byte `json:"ephemeral_key,omitempty"`
+	Threshold    int    `json:"threshold,omitempty"`
+
+	Nonce []byte `json:"nonce"`
+}
+
+type bundle struct {
+	bundleHeader
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type bundlePayload struct {
+	Credentials []CompCredentials `json:"credentials"`
+
+	// Set in snapshot files.
+	Snapshot string `json:"snapshot,omitempty"`
+	Note     string `json:"note,omitempty"`
+}
+
+// Write every stored credential to w as a bundle encrypted with AES-256-GCM
+// under a key derived from key, and return how many were written.
+func (ccs *CompCredStore) Export(w io.Writer, key BundleKey) (int, error) {
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return 0, err
+	}
+	payload := bundlePayload{Credentials: sortedCreds(creds)}
+	if err := sealBundle(w, key, time.Now(), payload); err != nil {
+		return 0, err
+	}
+	return len(payload.Credentials), nil
+}
+
+// Get credentials ordered by xname, leaving out any with no xname.
+func sortedCreds(creds map[string]CompCredentials) []CompCredentials {
+	list := make([]CompCredentials, 0, len(creds))
+	for xname, cred := range creds {
+		if xname != "" {
+			list = append(list, cred)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Xname < list[j].Xname
+	})
+	return list
+}
+
+// Encrypt a payload under a key derived from key and write it to w.
+func sealBundle(w io.Writer, key BundleKey, created time.Time, payload bundlePayload) error {
+	b := bundle{bundleHeader: bundleHeader{
+		Format:  BundleFormat,
+		Version: BundleVersion,
+		Created: created.UTC().Format(time.RFC3339),
+	}}
+	var (
+		aeadKey []byte
+		err     error
+	)
+	kinds := 0
+	for _, set := range []bool{key.Passphrase != "", key.PublicKey != nil, key.Key != nil} {
+		if set {
+			kinds++
+		}
+	}
+	switch {
+	case kinds > 1:
+		return fmt.Errorf("a bundle key must be one of a passphrase, a public key or a split key")
+	case key.Passphrase != "":
+		b.KDF, b.ScryptN, b.ScryptR, b.ScryptP = "scrypt", scryptN, scryptR, scryptP
+		b.Salt = make([]byte, 16)
+		if _, err := rand.Read(b.Salt); err != nil {
+			return err
+		}
+		if aeadKey, err = scrypt.Key([]byte(key.Passphrase), b.Salt, b.ScryptN, b.ScryptR, b.ScryptP, 32); err != nil {
+			return err
+		}
+	case key.PublicKey != nil:
+		if key.PublicKey.Curve() != ecdh.X25519() {
+			return fmt.Errorf("bundle public keys must be X25519 keys")
+		}
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		b.KDF = "x25519"
+		b.EphemeralKey = ephemeral.PublicKey().Bytes()
+		if aeadKey, err = x25519Key(ephemeral, key.PublicKey, b.EphemeralKey, key.PublicKey.Bytes()); err != nil {
+			return err
+		}
+	case key.Key != nil:
+		if len(key.Key) != 32 || key.threshold < 2 {
+			return fmt.Errorf("split bundle keys must come from SplitBundleKey")
+		}
+		b.KDF, b.Threshold, aeadKey = "shamir", key.threshold, key.Key
+	default:
+		return fmt.Errorf("a passphrase, public key or split key is required to export credentials")
+	}
+
+	aead, err := newBundleAEAD(aeadKey)
+	if err != nil {
+		return err
+	}
+	b.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(b.Nonce); err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	aad, err := json.Marshal(b.bundleHeader)
+	if err != nil {
+		return err
+	}
+	b.Ciphertext = aead.Seal(nil, b.Nonce, plaintext, aad)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Restore the credentials in a bundle written by Export, and return what
+// was, or with DryRun would be, done with each, as for ImportCompCreds.
+func (ccs *CompCredStore) Import(r io.Reader, key BundleKey, opts ImportOptions) ([]ImportChange, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	_, payload, err := openBundle(r, key)
+	if err != nil {
+		return nil, err
+	}
+	return ccs.ImportCompCreds(payload.Credentials, opts)
+}
+
+// Store credentials read from a bundle or an export file, and return what
+// was, or with DryRun would be, done with each, ordered by xname.
+// Credentials already stored and identical to the imported ones are left
+// alone; those that differ are handled by the conflict policy. With
+// ConflictFail, nothing is stored if any differ, and the conflicting ones
+// are returned along with an error. As with RestoreSnapshot, the store's
+// PasswordReuse policy does not refuse imported passwords.
+func (ccs *CompCredStore) ImportCompCreds(creds []CompCredentials, opts ImportOptions) ([]ImportChange, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	conflict := opts.Conflict
+	if conflict == "" {
+		conflict = ConflictSkip
+	}
+	creds = append([]CompCredentials(nil), creds...)
+	sort.Slice(creds, func(i, j int) bool { return creds[i].Xname < creds[j].Xname })
+
+	changes := make([]ImportChange, 0, len(creds))
+	var conflicts []string
+	for _, cred := range creds {
+		existing, err := ccs.GetCompCred(cred.Xname)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get credentials for %s: %v", cred.Xname, err)
+		}
+
+		change := ImportChange{Xname: cred.Xname, Change: "created"}
+		if existing.Xname != "" {
+			change.Fields = ChangedFields(existing, cred)
+			switch {
+			case len(change.Fields) == 0:
+				change.Change = "unchanged"
+			case conflict == ConflictOverwrite:
+				change.Change = "overwritten"
+			case conflict == ConflictFail:
+				change.Change = "conflict"
+				conflicts = append(conflicts, cred.Xname)
+			default:
+				change.Change = "skipped"
+			}
+		}
+		changes = append(changes, change)
+	}
+	if len(conflicts) > 0 {
+		return changes, fmt.Errorf("credentials for %s differ from the stored ones", strings.Join(conflicts, ", "))
+	}
+	if opts.DryRun {
+		return changes, nil
+	}
+
+	for i, change := range changes {
+		if change.Change != "created" && change.Change != "overwritten" {
+			continue
+		}
+		// A restore is not refused for password reuse.
+		if err := ccs.storeCompCred(creds[i], "", storeRestored); err != nil {
+			return changes, fmt.Errorf("unable to store credentials for %s: %v", change.Xname, err)
+		}
+	}
+	return changes, nil
+}
+
+func (opts ImportOptions) check() error {
+	switch opts.Conflict {
+	case "", ConflictSkip, ConflictOverwrite, ConflictFail:
+		return nil
+	}
+	return fmt.Errorf("unknown conflict policy %q", opts.Conflict)
+}
+
+// Decrypt a bundle and check its contents, returning its header and its
+// payload with the credentials ordered by xname.
+func openBundle(r io.Reader, key BundleKey) (bundleHeader, bundlePayload, error) {
+	var b bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("unable to read bundle: %v", err)
+	}
+	if b.Format != BundleFormat {
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("not a credentials bundle")
+	}
+	if b.Version < 1 || b.Version > BundleVersion {
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+
+	var (
+		aeadKey []byte
+		err     error
+	)
+	switch b.KDF {
+	case "scrypt":
+		if key.Passphrase == "" {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("the bundle is encrypted with a passphrase")
+		}
+		if b.ScryptN < 2 || b.ScryptN > maxScryptN || b.ScryptR < 1 || b.ScryptP < 1 || b.ScryptR*b.ScryptP > 16 {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("unsupported scrypt parameters in bundle")
+		}
+		if aeadKey, err = scrypt.Key([]byte(key.Passphrase), b.Salt, b.ScryptN, b.ScryptR, b.ScryptP, 32); err != nil {
+			return bundleHeader{}, bundlePayload{}, err
+		}
+	case "x25519":
+		if key.PrivateKey == nil {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("the bundle is encrypted with a public key")
+		}
+		ephemeral, err := ecdh.X25519().NewPublicKey(b.EphemeralKey)
+		if err != nil {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("invalid ephemeral key in bundle: %v", err)
+		}
+		if aeadKey, err = x25519Key(key.PrivateKey, ephemeral, b.EphemeralKey, key.PrivateKey.PublicKey().Bytes()); err != nil {
+			return bundleHeader{}, bundlePayload{}, err
+		}
+	case "shamir":
+		if key.Key == nil {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("the bundle key is split into shares; %d are needed", b.Threshold)
+		}
+		aeadKey = key.Key
+	default:
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("unsupported bundle key derivation %q", b.KDF)
+	}
+
+	aead, err := newBundleAEAD(aeadKey)
+	if err != nil {
+		return bundleHeader{}, bundlePayload{}, err
+	}
+	if len(b.Nonce) != aead.NonceSize() {
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("invalid nonce in bundle")
+	}
+	aad, err := json.Marshal(b.bundleHeader)
+	if err != nil {
+		return bundleHeader{}, bundlePayload{}, err
+	}
+	plaintext, err := aead.Open(nil, b.Nonce, b.Ciphertext, aad)
+	if err != nil {
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("unable to decrypt bundle: wrong key, or the bundle has been altered")
+	}
+
+	var payload bundlePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return bundleHeader{}, bundlePayload{}, fmt.Errorf("unable to read bundle contents: %v", err)
+	}
+	seen := make(map[string]bool, len(payload.Credentials))
+	for i, cred := range payload.Credentials {
+		if cred.Xname == "" {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("bundle record %d has no xname", i)
+		}
+		if seen[cred.Xname] {
+			return bundleHeader{}, bundlePayload{}, fmt.Errorf("%s appears more than once in the bundle", cred.Xname)
+		}
+		seen[cred.Xname] = true
+	}
+	sort.Slice(payload.Credentials, func(i, j int) bool {
+		return payload.Credentials[i].Xname < payload.Credentials[j].Xname
+	})
+	return b.bundleHeader, payload, nil
+}
+
+// Derive the key of an X25519 bundle from the shared secret of one side's
+// private key and the other's public key, bound to the ephemeral and
+// recipient public keys.
+func x25519Key(priv *ecdh.PrivateKey, pub *ecdh.PublicKey, ephemeral, recipient []byte) ([]byte, error) {
+	if priv.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("bundle private keys must be X25519 keys")
+	}
+	shared, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte(nil), ephemeral...), recipient...)
+	return hkdf.Key(sha256.New, shared, salt, bundleKeyInfo, 32)
+}
+
+func newBundleAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
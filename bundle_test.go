@@ -0,0 +1,187 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+var bundleCreds = []cc.CompCredentials{
+	{Xname: "x0c0s1b0", URL: "10.4.0.21", Username: "root", Password: "secret-one"},
+	{Xname: "x0c0s2b0", URL: "10.4.0.22", Username: "root", Password: "secret-two", SNMPAuthPass: "snmp-auth"},
+	{Xname: "x0c0s3b0", URL: "10.4.0.23", Username: "root", Password: "secret-three", SSHKey: "ssh-key"},
+}
+
+func newBundleStore(t *testing.T, creds ...cc.CompCredentials) *cc.CompCredStore {
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	for _, cred := range creds {
+		if err := ccs.StoreCompCred(cred); err != nil {
+			t.Fatalf("Unable to seed store: %v", err)
+		}
+	}
+	return ccs
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	recipient, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+
+	var tests = []struct {
+		exportKey cc.BundleKey
+		importKey cc.BundleKey
+		importErr bool
+	}{
+		{cc.BundleKey{Passphrase: "correct horse"}, cc.BundleKey{Passphrase: "correct horse"}, false},
+		{cc.BundleKey{Passphrase: "correct horse"}, cc.BundleKey{Passphrase: "wrong horse"}, true},
+		{cc.BundleKey{Passphrase: "correct horse"}, cc.BundleKey{PrivateKey: recipient}, true},
+		{cc.BundleKey{PublicKey: recipient.PublicKey()}, cc.BundleKey{PrivateKey: recipient}, false},
+		{cc.BundleKey{PublicKey: recipient.PublicKey()}, cc.BundleKey{PrivateKey: other}, true},
+		{cc.BundleKey{PublicKey: recipient.PublicKey()}, cc.BundleKey{Passphrase: "correct horse"}, true},
+	}
+
+	for i, test := range tests {
+		var buf bytes.Buffer
+		n, err := newBundleStore(t, bundleCreds...).Export(&buf, test.exportKey)
+		if err != nil || n != len(bundleCreds) {
+			t.Fatalf("Test %v Failed: Expected %d exported but got %d, %v", i, len(bundleCreds), n, err)
+		}
+		if strings.Contains(buf.String(), "secret-one") {
+			t.Errorf("Test %v Failed: Expected the bundle not to contain secrets in the clear", i)
+		}
+
+		ccs := newBundleStore(t)
+		changes, err := ccs.Import(&buf, test.importKey, cc.ImportOptions{})
+		if (err != nil) != test.importErr {
+			t.Errorf("Test %v Failed: Expected import error %v but got %v", i, test.importErr, err)
+		}
+		if test.importErr {
+			continue
+		}
+		if len(changes) != len(bundleCreds) {
+			t.Errorf("Test %v Failed: Expected %d changes but got %v", i, len(bundleCreds), changes)
+		}
+		for _, cred := range bundleCreds {
+			stored, _ := ccs.GetCompCred(cred.Xname)
+			if !reflect.DeepEqual(stored, cred) {
+				t.Errorf("Test %v Failed: Expected %#v but got %#v", i, cred, stored)
+			}
+		}
+	}
+
+	if _, err := newBundleStore(t).Export(&bytes.Buffer{}, cc.BundleKey{}); err == nil {
+		t.Errorf("Expected an error exporting without a key")
+	}
+}
+
+func TestBundleTampering(t *testing.T) {
+	key := cc.BundleKey{Passphrase: "correct horse"}
+	var buf bytes.Buffer
+	if _, err := newBundleStore(t, bundleCreds...).Export(&buf, key); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var tests = []struct {
+		field string
+		value interface{}
+		err   string
+	}{
+		{"created", "2000-01-01T00:00:00Z", "altered"},
+		{"ciphertext", []byte("not the ciphertext"), "altered"},
+		{"version", 2, "unsupported bundle version"},
+		{"format", "something-else", "not a credentials bundle"},
+		{"scrypt_n", 1 << 30, "unsupported scrypt parameters"},
+	}
+
+	for i, test := range tests {
+		var b map[string]interface{}
+		json.Unmarshal(buf.Bytes(), &b)
+		b[test.field] = test.value
+		altered, _ := json.Marshal(b)
+
+		_, err := newBundleStore(t).Import(bytes.NewReader(altered), key, cc.ImportOptions{})
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Test %v Failed: Expected an error containing %q but got %v", i, test.err, err)
+		}
+	}
+}
+
+func TestBundleImportConflicts(t *testing.T) {
+	key := cc.BundleKey{Passphrase: "correct horse"}
+	var buf bytes.Buffer
+	if _, err := newBundleStore(t, bundleCreds...).Export(&buf, key); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// The store has the first set unchanged, the second with a different
+	// password, and not the third.
+	changed := bundleCreds[1]
+	changed.Password = "rotated"
+
+	var tests = []struct {
+		opts      cc.ImportOptions
+		changes   []string
+		stored    []string
+		importErr bool
+	}{
+		{cc.ImportOptions{}, []string{"unchanged", "skipped", "created"}, []string{"secret-one", "rotated", "secret-three"}, false},
+		{cc.ImportOptions{Conflict: cc.ConflictOverwrite}, []string{"unchanged", "overwritten", "created"}, []string{"secret-one", "secret-two", "secret-three"}, false},
+		{cc.ImportOptions{Conflict: cc.ConflictFail}, []string{"unchanged", "conflict", "created"}, []string{"secret-one", "rotated", ""}, true},
+		{cc.ImportOptions{Conflict: cc.ConflictOverwrite, DryRun: true}, []string{"unchanged", "overwritten", "created"}, []string{"secret-one", "rotated", ""}, false},
+		{cc.ImportOptions{Conflict: "merge"}, nil, []string{"secret-one", "rotated", ""}, true},
+	}
+
+	for i, test := range tests {
+		ccs := newBundleStore(t, bundleCreds[0], changed)
+		changes, err := ccs.Import(bytes.NewReader(buf.Bytes()), key, test.opts)
+		if (err != nil) != test.importErr {
+			t.Errorf("Test %v Failed: Expected import error %v but got %v", i, test.importErr, err)
+		}
+
+		var got []string
+		for _, change := range changes {
+			got = append(got, change.Change)
+		}
+		if !reflect.DeepEqual(got, test.changes) {
+			t.Errorf("Test %v Failed: Expected changes %v but got %v", i, test.changes, got)
+		}
+		if len(changes) > 1 && !reflect.DeepEqual(changes[1].Fields, []string{"password"}) {
+			t.Errorf("Test %v Failed: Expected the password to be reported changed but got %v", i, changes[1].Fields)
+		}
+
+		for j, cred := range bundleCreds {
+			stored, _ := ccs.GetCompCred(cred.Xname)
+			if stored.Password != test.stored[j] {
+				t.Errorf("Test %v Failed: Expected %s to have password %q but got %q", i, cred.Xname, test.stored[j], stored.Password)
+			}
+		}
+	}
+}
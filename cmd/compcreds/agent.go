@@ -0,0 +1,87 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Cray-HPE/hms-compcredentials/agent"
+)
+
+const defaultAgentSocket = "/run/compcreds/agent.sock"
+
+func (c *cli) agent(name string, args []string) int {
+	var (
+		opts      options
+		socket    string
+		rulesFile string
+		cacheTTL  time.Duration
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&socket, "socket", defaultAgentSocket, "Path of the Unix socket to serve on")
+	fs.StringVar(&rulesFile, "rules", "", "JSON file of authorisation rules (required)")
+	fs.DurationVar(&cacheTTL, "cache-ttl", agent.DefaultCacheTTL, "How long to cache credentials")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 || rulesFile == "" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	buf, err := os.ReadFile(rulesFile)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	var rules []agent.Rule
+	if err := json.Unmarshal(buf, &rules); err != nil {
+		return c.errorf("%s: %v", rulesFile, err)
+	}
+
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	a := agent.NewAgent(agent.Config{
+		Store:      ccs,
+		SocketPath: socket,
+		Rules:      rules,
+		CacheTTL:   cacheTTL,
+	})
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		<-sigs
+		a.Close()
+	}()
+
+	if err := a.ListenAndServe(); err != nil {
+		return c.errorf("%v", err)
+	}
+	os.Remove(socket)
+	return exitOK
+}
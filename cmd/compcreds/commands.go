@@ -0,0 +1,600 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"crypto/ecdh"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+func (c *cli) get(name string, args []string) int {
+	var (
+		opts    options
+		version int
+	)
+	fs := c.flagSet(name, &opts)
+	fs.IntVar(&version, "version", 0, "Show this version of the credentials, from 'compcreds history', instead of the current one")
+	if !c.parse(fs, &opts, args) || fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	creds := make(map[string]cc.CompCredentials)
+	status := exitOK
+	for _, xname := range fs.Args() {
+		var (
+			cred cc.CompCredentials
+			err  error
+		)
+		if version > 0 {
+			cred, err = ccs.GetCompCredVersion(xname, version)
+		} else {
+			cred, err = ccs.GetCompCred(xname)
+		}
+		if err != nil {
+			status = c.errorf("unable to get credentials for %s: %v", xname, err)
+			continue
+		}
+		if cred.Xname == "" {
+			status = c.errorf("no credentials stored for %s", xname)
+			continue
+		}
+		creds[xname] = cred
+	}
+
+	if err := c.printCreds(&opts, creds); err != nil {
+		return c.errorf("%v", err)
+	}
+	return status
+}
+
+func (c *cli) list(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return c.errorf("unable to list credentials: %v", err)
+	}
+	if err := c.printCreds(&opts, creds); err != nil {
+		return c.errorf("%v", err)
+	}
+	return exitOK
+}
+
+func (c *cli) set(name string, args []string) int {
+	var (
+		opts                             options
+		username, url                    string
+		passwordFile, snmpAuth, snmpPriv string
+		sshKey, sshHostKey               string
+		expires                          string
+		passwordStdin                    bool
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&username, "username", "", "Account username")
+	fs.StringVar(&url, "url", "", "Component URL")
+	fs.StringVar(&passwordFile, "password-file", "", "Read the password from this file")
+	fs.BoolVar(&passwordStdin, "password-stdin", false, "Read the password from the first line of standard input")
+	fs.StringVar(&snmpAuth, "snmp-auth-file", "", "Read the SNMP authentication password from this file")
+	fs.StringVar(&snmpPriv, "snmp-priv-file", "", "Read the SNMP privacy password from this file")
+	fs.StringVar(&sshKey, "ssh-key-file", "", "Read the SSH private key from this file")
+	fs.StringVar(&sshHostKey, "ssh-host-key-file", "", "Read the pinned SSH host key from this file")
+	fs.StringVar(&expires, "expires", "", "When the credentials are due to be rotated, in RFC 3339 format")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	if passwordFile != "" && passwordStdin {
+		fmt.Fprintf(c.stderr, "compcreds: --password-file and --password-stdin are mutually exclusive\n")
+		return exitUsage
+	}
+	xname := fs.Arg(0)
+
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	cred, err := ccs.GetCompCred(xname)
+	if err != nil {
+		return c.errorf("unable to get credentials for %s: %v", xname, err)
+	}
+	cred.Xname = xname
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "username":
+			cred.Username = username
+		case "url":
+			cred.URL = url
+		case "expires":
+			cred.Expires = expires
+		}
+	})
+
+	secrets := []struct {
+		path  string
+		value *string
+	}{
+		{passwordFile, &cred.Password},
+		{snmpAuth, &cred.SNMPAuthPass},
+		{snmpPriv, &cred.SNMPPrivPass},
+		{sshKey, &cred.SSHKey},
+		{sshHostKey, &cred.SSHHostKey},
+	}
+	for _, s := range secrets {
+		if s.path == "" {
+			continue
+		}
+		if *s.value, err = readSecretFile(s.path); err != nil {
+			return c.errorf("%v", err)
+		}
+	}
+	if passwordStdin {
+		line, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return c.errorf("unable to read password from standard input: %v", err)
+		}
+		cred.Password = strings.TrimRight(line, "\r\n")
+	}
+
+	if err := ccs.StoreCompCred(cred); err != nil {
+		return c.errorf("unable to store credentials for %s: %v", xname, err)
+	}
+	return exitOK
+}
+
+func (c *cli) delete(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	status := exitOK
+	for _, xname := range fs.Args() {
+		if err := ccs.DeleteCompCred(xname); err != nil {
+			status = c.errorf("unable to delete credentials for %s: %v", xname, err)
+		}
+	}
+	return status
+}
+
+// Read an export file, checking that every record is usable.
+func readExportFile(path string) (map[string]cc.CompCredentials, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []cc.CompCredentials
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	creds := make(map[string]cc.CompCredentials, len(list))
+	for i, cred := range list {
+		if cred.Xname == "" {
+			return nil, fmt.Errorf("%s: record %d has no xname", path, i)
+		}
+		if _, dup := creds[cred.Xname]; dup {
+			return nil, fmt.Errorf("%s: %s appears more than once", path, cred.Xname)
+		}
+		creds[cred.Xname] = cred
+	}
+	return creds, nil
+}
+
+func hasRedacted(cred cc.CompCredentials) bool {
+	return cred.Password == cc.RedactedValue ||
+		cred.SNMPAuthPass == cc.RedactedValue ||
+		cred.SNMPPrivPass == cc.RedactedValue ||
+		cred.SSHKey == cc.RedactedValue
+}
+
+func (c *cli) diff(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	fileCreds, err := readExportFile(fs.Arg(0))
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+	storeCreds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return c.errorf("unable to list credentials: %v", err)
+	}
+
+	all := make(map[string]cc.CompCredentials)
+	for xname, cred := range fileCreds {
+		all[xname] = cred
+	}
+	for xname, cred := range storeCreds {
+		all[xname] = cred
+	}
+
+	var entries []diffEntry
+	for _, xname := range sortedXnames(all) {
+		fileCred, inFile := fileCreds[xname]
+		storeCred, inStore := storeCreds[xname]
+		switch {
+		case !inStore:
+			entries = append(entries, diffEntry{Xname: xname, Change: "only-in-file"})
+		case !inFile:
+			entries = append(entries, diffEntry{Xname: xname, Change: "only-in-store"})
+		default:
+			if fields := cc.ChangedFields(storeCred, fileCred); len(fields) > 0 {
+				entries = append(entries, diffEntry{Xname: xname, Change: "changed", Fields: fields})
+			}
+		}
+	}
+
+	if err := c.printDiff(&opts, entries); err != nil {
+		return c.errorf("%v", err)
+	}
+	if len(entries) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// Read the key for an encrypted bundle from a passphrase file or a PEM
+// X25519 key file. Returns false if neither was given.
+func readBundleKey(passphraseFile, keyFile string, private bool) (cc.BundleKey, bool, error) {
+	var key cc.BundleKey
+	switch {
+	case passphraseFile != "" && keyFile != "":
+		return key, true, fmt.Errorf("a passphrase and a key cannot both be given")
+	case passphraseFile != "":
+		passphrase, err := readSecretFile(passphraseFile)
+		if err != nil {
+			return key, true, err
+		}
+		if passphrase == "" {
+			return key, true, fmt.Errorf("%s: the passphrase is empty", passphraseFile)
+		}
+		key.Passphrase = passphrase
+		return key, true, nil
+	case keyFile == "":
+		return key, false, nil
+	}
+
+	buf, err := os.ReadFile(keyFile)
+	if err != nil {
+		return key, true, err
+	}
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return key, true, fmt.Errorf("%s: no PEM key found", keyFile)
+	}
+	var parsed interface{}
+	if private {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return key, true, fmt.Errorf("%s: %v", keyFile, err)
+	}
+	switch k := parsed.(type) {
+	case *ecdh.PrivateKey:
+		key.PrivateKey = k
+	case *ecdh.PublicKey:
+		key.PublicKey = k
+	default:
+		return key, true, fmt.Errorf("%s: not an X25519 key", keyFile)
+	}
+	return key, true, nil
+}
+
+// Write bundle key shares to share-1.txt and so on in dir, refusing to
+// replace existing ones.
+func writeShares(dir string, shares []string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for i, share := range shares {
+		path := filepath.Join(dir, fmt.Sprintf("share-%d.txt", i+1))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(f, share)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read bundle key shares from files and, if stdin is set, standard input,
+// one per line.
+func (c *cli) readShares(paths []string, stdin bool) ([]string, error) {
+	var shares []string
+	for _, path := range paths {
+		share, err := readSecretFile(path)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	if stdin {
+		scanner := bufio.NewScanner(c.stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				shares = append(shares, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("unable to read shares from standard input: %v", err)
+		}
+	}
+	return shares, nil
+}
+
+func (c *cli) export(name string, args []string) int {
+	var (
+		opts           options
+		output         string
+		passphraseFile string
+		recipient      string
+		shares         int
+		threshold      int
+		shareDir       string
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&output, "output", "", "Write to this file instead of standard output")
+	fs.StringVar(&passphraseFile, "passphrase-file", "", "Write an encrypted bundle, with the passphrase in this file")
+	fs.StringVar(&recipient, "recipient", "", "Write an encrypted bundle for the X25519 public key in this PEM file")
+	fs.IntVar(&shares, "shares", 0, "Write an encrypted bundle whose key is split into this many shares")
+	fs.IntVar(&threshold, "threshold", 0, "With --shares, how many shares are needed to import")
+	fs.StringVar(&shareDir, "share-dir", "", "With --shares, the directory the shares are written to")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if (shares > 0) != (shareDir != "") || (shares > 0) != (threshold > 0) {
+		fmt.Fprintf(c.stderr, "compcreds: --shares, --threshold and --share-dir must be given together\n")
+		return exitUsage
+	}
+	key, encrypted, err := readBundleKey(passphraseFile, recipient, false)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if shares > 0 {
+		if encrypted {
+			return c.errorf("--shares cannot be used with a passphrase or key")
+		}
+		var shareList []string
+		if key, shareList, err = cc.SplitBundleKey(shares, threshold); err != nil {
+			return c.errorf("%v", err)
+		}
+		if err := writeShares(shareDir, shareList); err != nil {
+			return c.errorf("%v", err)
+		}
+		fmt.Fprintf(c.stderr, "Wrote %d key shares to %s; give each to a different holder\n", shares, shareDir)
+		encrypted = true
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	if output == "" {
+		return c.writeExport(c.stdout, ccs, &opts, key, encrypted)
+	}
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	status := c.writeExport(f, ccs, &opts, key, encrypted)
+	if err := f.Close(); err != nil && status == exitOK {
+		return c.errorf("unable to write %s: %v", output, err)
+	}
+	return status
+}
+
+// Write every stored credential to w, as an encrypted bundle or as JSON.
+func (c *cli) writeExport(w io.Writer, ccs *cc.CompCredStore, opts *options, key cc.BundleKey, encrypted bool) int {
+	if encrypted {
+		n, err := ccs.Export(w, key)
+		if err != nil {
+			return c.errorf("unable to export credentials: %v", err)
+		}
+		fmt.Fprintf(c.stderr, "Exported %d credentials\n", n)
+		return exitOK
+	}
+
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return c.errorf("unable to list credentials: %v", err)
+	}
+	list := make([]cc.CompCredentials, 0, len(creds))
+	for _, xname := range sortedXnames(creds) {
+		cred := creds[xname]
+		if !opts.showSecrets {
+			cred = cred.Redacted()
+		}
+		list = append(list, cred)
+	}
+	if !opts.showSecrets {
+		fmt.Fprintf(c.stderr, "compcreds: secrets are redacted; use --show-secrets for an export that can be imported\n")
+	}
+	if err := writeJSON(w, list); err != nil {
+		return c.errorf("%v", err)
+	}
+	return exitOK
+}
+
+func (c *cli) importFile(name string, args []string) int {
+	var (
+		opts           options
+		overwrite      bool
+		dryRun         bool
+		passphraseFile string
+		identity       string
+		conflict       string
+		shareFiles     []string
+		sharesStdin    bool
+	)
+	fs := c.flagSet(name, &opts)
+	fs.BoolVar(&overwrite, "overwrite", false, "Replace credentials that are already stored")
+	fs.BoolVar(&dryRun, "dry-run", false, "Show what would be stored without storing it")
+	fs.StringVar(&passphraseFile, "passphrase-file", "", "Read an encrypted bundle, with the passphrase in this file")
+	fs.StringVar(&identity, "identity", "", "Read an encrypted bundle with the X25519 private key in this PEM file")
+	fs.Func("share-file", "Read a bundle key share from this file; repeat for each share", func(path string) error {
+		shareFiles = append(shareFiles, path)
+		return nil
+	})
+	fs.BoolVar(&sharesStdin, "shares-stdin", false, "Read bundle key shares from standard input, one per line")
+	fs.StringVar(&conflict, "conflict", "", "With a bundle, what to do with stored credentials that differ: skip, overwrite or fail")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	key, encrypted, err := readBundleKey(passphraseFile, identity, true)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if len(shareFiles) > 0 || sharesStdin {
+		if encrypted {
+			return c.errorf("shares cannot be used with a passphrase or key")
+		}
+		shareList, err := c.readShares(shareFiles, sharesStdin)
+		if err != nil {
+			return c.errorf("%v", err)
+		}
+		if key, err = cc.CombineBundleShares(shareList); err != nil {
+			return c.errorf("%v", err)
+		}
+		encrypted = true
+	}
+	if encrypted {
+		if conflict == "" && overwrite {
+			conflict = string(cc.ConflictOverwrite)
+		}
+		return c.importBundle(&opts, fs.Arg(0), key, cc.ImportOptions{Conflict: cc.ConflictPolicy(conflict), DryRun: dryRun})
+	}
+	if conflict != "" {
+		fmt.Fprintf(c.stderr, "compcreds: --conflict needs --passphrase-file, --identity or shares\n")
+		return exitUsage
+	}
+
+	creds, err := readExportFile(fs.Arg(0))
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	for _, xname := range sortedXnames(creds) {
+		if hasRedacted(creds[xname]) {
+			return c.errorf("%s has redacted secrets; export with --show-secrets to import", xname)
+		}
+	}
+
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	conflict = string(cc.ConflictSkip)
+	if overwrite {
+		conflict = string(cc.ConflictOverwrite)
+	}
+	list := make([]cc.CompCredentials, 0, len(creds))
+	for _, xname := range sortedXnames(creds) {
+		list = append(list, creds[xname])
+	}
+	changes, importErr := ccs.ImportCompCreds(list, cc.ImportOptions{Conflict: cc.ConflictPolicy(conflict), DryRun: dryRun})
+	return c.printImport(&opts, changes, importErr)
+}
+
+func (c *cli) importBundle(opts *options, path string, key cc.BundleKey, importOpts cc.ImportOptions) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	defer f.Close()
+	ccs, ok := c.store(opts)
+	if !ok {
+		return exitError
+	}
+
+	changes, importErr := ccs.Import(f, key, importOpts)
+	return c.printImport(opts, changes, importErr)
+}
+
+// Show what an import did, and its error if it failed.
+func (c *cli) printImport(opts *options, changes []cc.ImportChange, importErr error) int {
+	entries := make([]diffEntry, len(changes))
+	for i, change := range changes {
+		entries[i] = diffEntry{Xname: change.Xname, Change: change.Change, Fields: change.Fields}
+	}
+	if len(entries) > 0 {
+		if err := c.printDiff(opts, entries); err != nil {
+			return c.errorf("%v", err)
+		}
+	}
+	if importErr != nil {
+		return c.errorf("%v", importErr)
+	}
+	return exitOK
+}
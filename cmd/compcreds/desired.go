@@ -0,0 +1,109 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Cray-HPE/hms-compcredentials/desired"
+)
+
+func (c *cli) plan(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	spec, err := desired.Load(fs.Arg(0))
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	p, err := desired.NewPlan(ccs, spec)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if err := c.printPlan(&opts, p); err != nil {
+		return c.errorf("%v", err)
+	}
+	if len(p.Changes) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+func (c *cli) apply(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	spec, err := desired.Load(fs.Arg(0))
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	p, err := desired.NewPlan(ccs, spec)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	applyErr := p.Apply()
+	if err := c.printPlan(&opts, p); err != nil {
+		return c.errorf("%v", err)
+	}
+	if applyErr != nil {
+		return c.errorf("%v", applyErr)
+	}
+	return exitOK
+}
+
+// Print a plan's changes, which name fields but never show their values.
+func (c *cli) printPlan(opts *options, p *desired.Plan) error {
+	if opts.format == "json" {
+		changes := p.Changes
+		if changes == nil {
+			changes = []desired.Change{}
+		}
+		return writeJSON(c.stdout, changes)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tACTION\tFIELDS\tGENERATE\tERROR")
+	for _, change := range p.Changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", change.Xname, change.Action,
+			strings.Join(change.Fields, ","), strings.Join(change.Generate, ","), change.Error)
+	}
+	return tw.Flush()
+}
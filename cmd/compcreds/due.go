@@ -0,0 +1,91 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+)
+
+type dueEntry struct {
+	Xname     string `json:"xname"`
+	Updated   string `json:"updated,omitempty"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+	Expires   string `json:"expires,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// List the credentials due for rotation. Exits with exitError if there are
+// any, so it can be used as a check.
+func (c *cli) due(name string, args []string) int {
+	var (
+		opts   options
+		maxAge time.Duration
+	)
+	fs := c.flagSet(name, &opts)
+	fs.DurationVar(&maxAge, "max-age", 0, "Also list credentials that last changed longer ago than this, such as 2160h for 90 days, or whose age is not known")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 || maxAge < 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	creds, err := ccs.GetRotationDueCompCreds(maxAge)
+	if err != nil {
+		return c.errorf("unable to read credentials: %v", err)
+	}
+	now := time.Now()
+	entries := make([]dueEntry, 0, len(creds))
+	for _, xname := range sortedXnames(creds) {
+		cred := creds[xname]
+		entry := dueEntry{Xname: xname, Updated: cred.Updated, UpdatedBy: cred.UpdatedBy, Expires: cred.Expires, Reason: "expired"}
+		if !cred.Expired(now) {
+			entry.Reason = "too old"
+			if _, known := cred.Age(now); !known {
+				entry.Reason = "age unknown"
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if opts.format == "json" {
+		err = writeJSON(c.stdout, entries)
+	} else {
+		tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "XNAME\tUPDATED\tUPDATED BY\tEXPIRES\tREASON")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Xname, e.Updated, e.UpdatedBy, e.Expires, e.Reason)
+		}
+		err = tw.Flush()
+	}
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if len(entries) > 0 {
+		return exitError
+	}
+	return exitOK
+}
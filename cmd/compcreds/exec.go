@@ -0,0 +1,69 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Cray-HPE/hms-compcredentials/credexec"
+)
+
+func (c *cli) exec(name string, args []string) int {
+	var (
+		opts  options
+		xname string
+		names credexec.EnvNames
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&xname, "xname", "", "Component whose credentials are injected (required)")
+	fs.StringVar(&names.Username, "username-env", credexec.DefaultEnvNames.Username, "Variable for the username; empty to omit")
+	fs.StringVar(&names.Password, "password-env", credexec.DefaultEnvNames.Password, "Variable for the password; empty to omit")
+	fs.StringVar(&names.URL, "url-env", credexec.DefaultEnvNames.URL, "Variable for the URL; empty to omit")
+	if !c.parse(fs, &opts, args) || fs.NArg() == 0 || xname == "" {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	// Let the child decide what an interrupt means; we just wait for it.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	code, err := credexec.Run(context.Background(), ccs, xname, fs.Args(), credexec.Options{
+		Env:    &names,
+		Stdin:  c.stdin,
+		Stdout: c.stdout,
+		Stderr: c.stderr,
+	})
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	return code
+}
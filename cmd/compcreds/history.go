@@ -0,0 +1,98 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+func (c *cli) history(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	versions, err := ccs.ListCompCredVersions(fs.Arg(0))
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if opts.format == "json" {
+		if versions == nil {
+			versions = []cc.CredentialVersion{}
+		}
+		if err := writeJSON(c.stdout, versions); err != nil {
+			return c.errorf("%v", err)
+		}
+		return exitOK
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tCREATED\tDELETED")
+	for _, v := range versions {
+		created := ""
+		if !v.Created.IsZero() {
+			created = v.Created.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%t\n", v.Version, created, v.Deleted)
+	}
+	if err := tw.Flush(); err != nil {
+		return c.errorf("%v", err)
+	}
+	return exitOK
+}
+
+func (c *cli) rollback(name string, args []string) int {
+	var opts options
+	fs := c.flagSet(name, &opts)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 2 {
+		fs.Usage()
+		return exitUsage
+	}
+	xname := fs.Arg(0)
+	version, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || version < 1 {
+		fmt.Fprintf(c.stderr, "compcreds: invalid version %q\n", fs.Arg(1))
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	if _, err := ccs.RollbackCompCred(xname, version); err != nil {
+		return c.errorf("%v", err)
+	}
+	fmt.Fprintf(c.stderr, "Rolled back the credentials for %s to version %d\n", xname, version)
+	return exitOK
+}
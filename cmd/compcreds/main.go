@@ -0,0 +1,337 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// compcreds is a command-line tool for inspecting and maintaining component
+// credentials through CompCredStore. Secrets are redacted in all output
+// unless --show-secrets is given, and are never accepted on the command line.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+	"github.com/hashicorp/vault/api"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type options struct {
+	vaultAddr   string
+	vaultBase   string
+	keyPath     string
+	format      string
+	showSecrets bool
+
+	fallbackPaths []string
+	migrateOnRead bool
+
+	kvVersion    int
+	historyLimit int
+
+	reuseRemember int
+	reuseKeyFile  string
+
+	updater     string
+	expireAfter time.Duration
+}
+
+// Create the CompCredStore the commands operate on. Replaced in tests.
+var newStore = func(opts *options) (*cc.CompCredStore, error) {
+	ss, err := newVaultAdapter(opts.vaultAddr, opts.vaultBase)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to connect to Vault: %v", err)
+	}
+	if opts.kvVersion == 2 {
+		return cc.NewCompCredStore(opts.keyPath, cc.NewKV2Adapter(ss)), nil
+	}
+	return cc.NewCompCredStore(opts.keyPath, ss), nil
+}
+
+// Create a VaultAdapter for the Vault at addr, or at VAULT_ADDR if addr is
+// empty. NewVaultAdapter only takes the address from the environment, so
+// for another address the adapter is configured and logged in here, as
+// NewVaultAdapter does.
+func newVaultAdapter(addr, basePath string) (*sstorage.VaultAdapter, error) {
+	if addr == "" {
+		ss, err := sstorage.NewVaultAdapter(basePath)
+		if err != nil {
+			return nil, err
+		}
+		return ss.(*sstorage.VaultAdapter), nil
+	}
+
+	authConfig := sstorage.DefaultAuthConfig()
+	if err := authConfig.ReadEnvironment(); err != nil {
+		return nil, err
+	}
+	config := api.DefaultConfig()
+	if err := config.ReadEnvironment(); err != nil {
+		return nil, err
+	}
+	config.Address = addr
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authConfig.LoadRole(); err != nil {
+		return nil, err
+	}
+	if err := authConfig.LoadJWT(); err != nil {
+		return nil, err
+	}
+	secret, err := client.Logical().Write(authConfig.GetAuthPath(), authConfig.GetAuthArgs())
+	if err != nil {
+		return nil, err
+	}
+	token, err := secret.TokenID()
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(token)
+
+	return &sstorage.VaultAdapter{
+		Config:     config,
+		Client:     sstorage.NewRealVaultApi(client),
+		AuthConfig: authConfig,
+		BasePath:   basePath,
+		VaultRetry: 1,
+	}, nil
+}
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(c *cli, name string, args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"get", "XNAME...", "Show the credentials for components", (*cli).get},
+		{"list", "", "Show the credentials for every component", (*cli).list},
+		{"set", "XNAME", "Create or update the credentials for a component", (*cli).set},
+		{"delete", "XNAME...", "Remove the credentials for components", (*cli).delete},
+		{"diff", "FILE", "Compare an export file with the stored credentials", (*cli).diff},
+		{"export", "", "Write every stored credential as JSON", (*cli).export},
+		{"import", "FILE", "Store the credentials from an export file", (*cli).importFile},
+		{"compare", "", "Compare the credentials with those in another store", (*cli).compare},
+		{"sync", "[XNAME...]", "Make another store's credentials match these, or the reverse", (*cli).sync},
+		{"migrate", "", "Copy every credential to another store, optionally deleting them here", (*cli).migrate},
+		{"plan", "FILE", "Show the changes that make the credentials match a desired-state file", (*cli).plan},
+		{"apply", "FILE", "Make the credentials match a desired-state file", (*cli).apply},
+		{"history", "XNAME", "List the kept versions of a component's credentials", (*cli).history},
+		{"due", "", "List the credentials due for rotation", (*cli).due},
+		{"rollback", "XNAME VERSION", "Make an earlier version of a component's credentials current", (*cli).rollback},
+		{"snapshot", "create|list|diff|restore|delete [NAME] [XNAME...]", "Take, compare and restore point-in-time snapshots", (*cli).snapshot},
+		{"agent", "", "Serve credentials to local processes over a Unix socket", (*cli).agent},
+		{"render", "", "Render credentials into config files from templates", (*cli).render},
+		{"exec", "-- COMMAND [ARGS]", "Run a command with a component's credentials in its environment", (*cli).exec},
+		{"passwd", "XNAME", "Change a component's BMC password through Redfish and store it", (*cli).passwd},
+		{"verify", "[XNAME...]", "Check that components accept their stored credentials", (*cli).verify},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		c.usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(c, cmd.name, args[1:])
+		}
+	}
+
+	fmt.Fprintf(stderr, "compcreds: unknown command %q\n", args[0])
+	c.usage()
+	return exitUsage
+}
+
+func (c *cli) usage() {
+	fmt.Fprintf(c.stderr, "Usage: compcreds COMMAND [FLAGS] [ARGS]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(c.stderr, "\nRun 'compcreds COMMAND -h' for the flags of a command.\n")
+}
+
+// Create a flag set for a command with the flags every command shares.
+func (c *cli) flagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("compcreds "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&opts.vaultAddr, "vault-addr", "", "Address of the Vault; defaults to VAULT_ADDR")
+	fs.StringVar(&opts.vaultBase, "vault-base", sstorage.DefaultBasePath, "Vault secrets engine path")
+	fs.StringVar(&opts.keyPath, "path", cc.DefaultCompCredPath, "Key space holding the credentials")
+	fs.StringVar(&opts.format, "format", "table", "Output format: table or json")
+	fs.BoolVar(&opts.showSecrets, "show-secrets", false, "Show passwords instead of redacting them")
+	fs.Func("fallback-path", "Read credentials not under --path from this older key space; repeat for each, in order", func(path string) error {
+		opts.fallbackPaths = append(opts.fallbackPaths, path)
+		return nil
+	})
+	fs.BoolVar(&opts.migrateOnRead, "migrate-on-read", false, "Store credentials read from a fallback path under --path")
+	fs.IntVar(&opts.kvVersion, "kv-version", 1, "Version of the Vault KV secrets engine at --vault-base: 1, or 2 to use its credential history")
+	fs.IntVar(&opts.historyLimit, "history-limit", 0, "With --kv-version 1, past versions of each credential to keep in the '<path>-history' key space")
+	fs.IntVar(&opts.reuseRemember, "reuse-remember", 0, "Refuse any of an account's last N passwords; needs --reuse-key-file")
+	fs.StringVar(&opts.reuseKeyFile, "reuse-key-file", "", "File holding the secret key password fingerprints are keyed with")
+	fs.StringVar(&opts.updater, "updater", "", "Record when credentials change, with this name as who changed them")
+	fs.DurationVar(&opts.expireAfter, "expire-after", 0, "With --updater, make credentials expire this long after they change, such as 2160h for 90 days")
+	for _, cmd := range commands {
+		if cmd.name == name {
+			fs.Usage = func() {
+				fmt.Fprintf(c.stderr, "Usage: compcreds %s [FLAGS] %s\n\n%s.\n\nFlags:\n", name, cmd.args, cmd.summary)
+				fs.PrintDefaults()
+			}
+		}
+	}
+	return fs
+}
+
+// Parse a command's flags and validate the shared ones. Returns false if the
+// command should exit with a usage error.
+func (c *cli) parse(fs *flag.FlagSet, opts *options, args []string) bool {
+	if err := fs.Parse(args); err != nil {
+		return false
+	}
+	if opts.format != "table" && opts.format != "json" {
+		fmt.Fprintf(c.stderr, "compcreds: unknown format %q\n", opts.format)
+		return false
+	}
+	if opts.kvVersion != 1 && opts.kvVersion != 2 {
+		fmt.Fprintf(c.stderr, "compcreds: unknown KV version %d\n", opts.kvVersion)
+		return false
+	}
+	if (opts.reuseRemember > 0) != (opts.reuseKeyFile != "") {
+		fmt.Fprintf(c.stderr, "compcreds: --reuse-remember and --reuse-key-file go together\n")
+		return false
+	}
+	if opts.expireAfter < 0 || (opts.expireAfter > 0 && opts.updater == "") {
+		fmt.Fprintf(c.stderr, "compcreds: --expire-after needs --updater and a positive duration\n")
+		return false
+	}
+	if opts.migrateOnRead && len(opts.fallbackPaths) == 0 {
+		fmt.Fprintf(c.stderr, "compcreds: --migrate-on-read needs --fallback-path\n")
+		return false
+	}
+	return true
+}
+
+func (c *cli) store(opts *options) (*cc.CompCredStore, bool) {
+	var reuse *cc.ReusePolicy
+	if opts.reuseKeyFile != "" {
+		key, err := readSecretFile(opts.reuseKeyFile)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "compcreds: unable to read the reuse key: %v\n", err)
+			return nil, false
+		}
+		reuse = &cc.ReusePolicy{Remember: opts.reuseRemember, Key: []byte(key)}
+	}
+	ccs, err := newStore(opts)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "compcreds: %v\n", err)
+		return nil, false
+	}
+	ccs.FallbackPaths = opts.fallbackPaths
+	ccs.MigrateOnRead = opts.migrateOnRead
+	ccs.HistoryLimit = opts.historyLimit
+	ccs.PasswordReuse = reuse
+	ccs.Metadata = nil
+	if opts.updater != "" {
+		ccs.Metadata = &cc.MetadataPolicy{Updater: opts.updater, MaxAge: opts.expireAfter}
+	}
+	return ccs, true
+}
+
+func (c *cli) errorf(format string, args ...interface{}) int {
+	fmt.Fprintf(c.stderr, "compcreds: "+format+"\n", args...)
+	return exitError
+}
+
+// Read a secret from a file, dropping one trailing newline.
+func readSecretFile(path string) (string, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(buf), "\n"), "\r"), nil
+}
+
+func sortedXnames(creds map[string]cc.CompCredentials) []string {
+	xnames := make([]string, 0, len(creds))
+	for xname := range creds {
+		xnames = append(xnames, xname)
+	}
+	sort.Strings(xnames)
+	return xnames
+}
+
+// Create a client for talking to BMCs, verifying their certificates against
+// the CAs in caFile if given, or the system roots.
+func newHTTPClient(caFile string, insecure bool, timeout time.Duration) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
@@ -0,0 +1,993 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+	"github.com/Cray-HPE/hms-compcredentials/desired"
+	"github.com/Cray-HPE/hms-compcredentials/redfishtest"
+	"github.com/Cray-HPE/hms-compcredentials/vaulttest"
+)
+
+var testCreds = []cc.CompCredentials{
+	{
+		Xname:    "x0c0s1b0",
+		URL:      "10.4.0.21/redfish/v1/UpdateService",
+		Username: "test1",
+		Password: "secret-one",
+	}, {
+		Xname:        "x0c0s2b0",
+		URL:          "10.4.0.22/redfish/v1/UpdateService",
+		Username:     "test2",
+		Password:     "secret-two",
+		SNMPAuthPass: "snmp-auth",
+		SNMPPrivPass: "snmp-priv",
+	},
+}
+
+// Point the commands at an in-memory store holding testCreds.
+func setupStore(t *testing.T) *cc.CompCredStore {
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	for _, cred := range testCreds {
+		if err := ccs.StoreCompCred(cred); err != nil {
+			t.Fatalf("Unable to seed store: %v", err)
+		}
+	}
+	orig := newStore
+	newStore = func(opts *options) (*cc.CompCredStore, error) {
+		return ccs, nil
+	}
+	t.Cleanup(func() { newStore = orig })
+	return ccs
+}
+
+func runCmd(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestGetList(t *testing.T) {
+	setupStore(t)
+
+	var tests = []struct {
+		args     []string
+		status   int
+		contains []string
+		excludes []string
+	}{
+		{
+			args:     []string{"get", "x0c0s1b0"},
+			status:   exitOK,
+			contains: []string{"x0c0s1b0", "test1", cc.RedactedValue},
+			excludes: []string{"secret-one", "x0c0s2b0"},
+		}, {
+			args:     []string{"get", "--show-secrets", "x0c0s1b0"},
+			status:   exitOK,
+			contains: []string{"secret-one"},
+		}, {
+			args:     []string{"get", "x9999c0s0b0"},
+			status:   exitError,
+			contains: []string{"XNAME"},
+		}, {
+			args:   []string{"get"},
+			status: exitUsage,
+		}, {
+			args:     []string{"list"},
+			status:   exitOK,
+			contains: []string{"x0c0s1b0", "x0c0s2b0"},
+			excludes: []string{"secret-one", "secret-two", "snmp-auth", "snmp-priv"},
+		}, {
+			args:   []string{"list", "--format", "xml"},
+			status: exitUsage,
+		}, {
+			args:   []string{"bogus"},
+			status: exitUsage,
+		},
+	}
+
+	for i, test := range tests {
+		status, stdout, _ := runCmd("", test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v", i, test.status, status)
+		}
+		for _, s := range test.contains {
+			if !strings.Contains(stdout, s) {
+				t.Errorf("Test %v Failed: Expected output to contain %q", i, s)
+			}
+		}
+		for _, s := range test.excludes {
+			if strings.Contains(stdout, s) {
+				t.Errorf("Test %v Failed: Expected output not to contain %q", i, s)
+			}
+		}
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	setupStore(t)
+
+	status, stdout, _ := runCmd("", "get", "--format", "json", "--show-secrets", "x0c0s2b0")
+	if status != exitOK {
+		t.Fatalf("Expected status %v but got %v", exitOK, status)
+	}
+	var creds []cc.CompCredentials
+	if err := json.Unmarshal([]byte(stdout), &creds); err != nil {
+		t.Fatalf("Unable to parse output: %v", err)
+	}
+	if !reflect.DeepEqual(creds, testCreds[1:]) {
+		t.Errorf("Expected %v but got %v", testCreds[1:], creds)
+	}
+}
+
+func TestSetDelete(t *testing.T) {
+	ccs := setupStore(t)
+	dir := t.TempDir()
+	authFile := filepath.Join(dir, "auth")
+	os.WriteFile(authFile, []byte("new-auth\n"), 0600)
+
+	status, _, stderr := runCmd("new-password\n", "set", "--username", "root",
+		"--password-stdin", "--snmp-auth-file", authFile, "x0c0s1b0")
+	if status != exitOK {
+		t.Fatalf("Expected status %v but got %v: %s", exitOK, status, stderr)
+	}
+	cred, _ := ccs.GetCompCred("x0c0s1b0")
+	expected := testCreds[0]
+	expected.Username = "root"
+	expected.Password = "new-password"
+	expected.SNMPAuthPass = "new-auth"
+	if !reflect.DeepEqual(cred, expected) {
+		t.Errorf("Expected %#v but got %#v", expected, cred)
+	}
+
+	status, _, _ = runCmd("", "set", "--url", "10.4.0.99", "x0c0s9b0")
+	if status != exitOK {
+		t.Fatalf("Expected status %v but got %v", exitOK, status)
+	}
+	cred, _ = ccs.GetCompCred("x0c0s9b0")
+	if cred.Xname != "x0c0s9b0" || cred.URL != "10.4.0.99" {
+		t.Errorf("Expected new credentials for x0c0s9b0 but got %#v", cred)
+	}
+
+	status, _, _ = runCmd("", "set", "--password-stdin", "--password-file", authFile, "x0c0s1b0")
+	if status != exitUsage {
+		t.Errorf("Expected status %v for conflicting password flags but got %v", exitUsage, status)
+	}
+
+	status, _, _ = runCmd("", "delete", "x0c0s9b0", "x0c0s1b0")
+	if status != exitOK {
+		t.Fatalf("Expected status %v but got %v", exitOK, status)
+	}
+	all, _ := ccs.GetAllCompCreds()
+	if len(all) != 1 || all["x0c0s2b0"].Xname == "" {
+		t.Errorf("Expected only x0c0s2b0 to remain but got %v", all)
+	}
+}
+
+func TestExportImportDiff(t *testing.T) {
+	ccs := setupStore(t)
+	dir := t.TempDir()
+	exportFile := filepath.Join(dir, "creds.json")
+	redactedFile := filepath.Join(dir, "redacted.json")
+
+	if status, _, _ := runCmd("", "export", "--show-secrets", "--output", exportFile); status != exitOK {
+		t.Fatalf("Expected export status %v but got %v", exitOK, status)
+	}
+	if fi, err := os.Stat(exportFile); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected export file with mode 0600 but got %v, %v", fi, err)
+	}
+	if status, _, _ := runCmd("", "export", "--output", redactedFile); status != exitOK {
+		t.Fatalf("Expected export status %v but got %v", exitOK, status)
+	}
+
+	status, stdout, _ := runCmd("", "diff", exportFile)
+	if status != exitOK || strings.Contains(stdout, "changed") {
+		t.Errorf("Expected no differences but got status %v: %s", status, stdout)
+	}
+
+	changed := testCreds[0]
+	changed.Password = "rotated"
+	ccs.StoreCompCred(changed)
+	ccs.DeleteCompCred("x0c0s2b0")
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s3b0", Password: "extra"})
+
+	status, stdout, _ = runCmd("", "diff", "--format", "json", exportFile)
+	if status != exitError {
+		t.Errorf("Expected diff status %v but got %v", exitError, status)
+	}
+	var entries []diffEntry
+	json.Unmarshal([]byte(stdout), &entries)
+	expected := []diffEntry{
+		{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"password"}},
+		{Xname: "x0c0s2b0", Change: "only-in-file"},
+		{Xname: "x0c0s3b0", Change: "only-in-store"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected diff %v but got %v", expected, entries)
+	}
+	if strings.Contains(stdout, "rotated") || strings.Contains(stdout, "secret-one") {
+		t.Errorf("Expected diff output not to reveal secrets")
+	}
+
+	if status, _, _ := runCmd("", "import", redactedFile); status != exitError {
+		t.Errorf("Expected a redacted export to be refused but got status %v", status)
+	}
+
+	status, _, _ = runCmd("", "import", "--dry-run", "--overwrite", exportFile)
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); status != exitOK || cred.Password != "rotated" {
+		t.Errorf("Expected dry run to store nothing but got status %v", status)
+	}
+
+	status, _, _ = runCmd("", "import", exportFile)
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); status != exitOK || cred.Password != "rotated" {
+		t.Errorf("Expected existing credentials to be skipped without --overwrite")
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s2b0"); !reflect.DeepEqual(cred, testCreds[1]) {
+		t.Errorf("Expected x0c0s2b0 to be restored")
+	}
+
+	status, _, _ = runCmd("", "import", "--overwrite", exportFile)
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); status != exitOK || cred.Password != "secret-one" {
+		t.Errorf("Expected x0c0s1b0 to be overwritten")
+	}
+}
+
+func TestExportImportBundle(t *testing.T) {
+	ccs := setupStore(t)
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	os.WriteFile(passphraseFile, []byte("correct horse\n"), 0600)
+	priv, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	pubDER, _ := x509.MarshalPKIXPublicKey(priv.PublicKey())
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubFile := filepath.Join(dir, "bundle.pub")
+	privFile := filepath.Join(dir, "bundle.key")
+	os.WriteFile(pubFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0600)
+	os.WriteFile(privFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600)
+	passBundle := filepath.Join(dir, "pass.bundle")
+	keyBundle := filepath.Join(dir, "key.bundle")
+
+	if status, _, stderr := runCmd("", "export", "--passphrase-file", passphraseFile, "--output", passBundle); status != exitOK {
+		t.Fatalf("Expected export status %v but got %v: %s", exitOK, status, stderr)
+	}
+	if status, _, stderr := runCmd("", "export", "--recipient", pubFile, "--output", keyBundle); status != exitOK {
+		t.Fatalf("Expected export status %v but got %v: %s", exitOK, status, stderr)
+	}
+	if status, _, _ := runCmd("", "export", "--passphrase-file", passphraseFile, "--recipient", pubFile); status != exitError {
+		t.Errorf("Expected both a passphrase and a key to be refused but got status %v", status)
+	}
+
+	changed := testCreds[0]
+	changed.Password = "rotated"
+	ccs.StoreCompCred(changed)
+	ccs.DeleteCompCred("x0c0s2b0")
+
+	var tests = []struct {
+		args     []string
+		status   int
+		entries  []diffEntry
+		password string
+	}{
+		{
+			args:   []string{"import", "--conflict", "fail", "--passphrase-file", passphraseFile, passBundle},
+			status: exitError,
+			entries: []diffEntry{
+				{Xname: "x0c0s1b0", Change: "conflict", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "created"},
+			},
+			password: "rotated",
+		}, {
+			args:   []string{"import", "--dry-run", "--overwrite", "--identity", privFile, keyBundle},
+			status: exitOK,
+			entries: []diffEntry{
+				{Xname: "x0c0s1b0", Change: "overwritten", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "created"},
+			},
+			password: "rotated",
+		}, {
+			args:     []string{"import", "--conflict", "overwrite", "--passphrase-file", privFile, passBundle},
+			status:   exitError,
+			password: "rotated",
+		}, {
+			args:     []string{"import", "--conflict", "overwrite", passBundle},
+			status:   exitUsage,
+			password: "rotated",
+		}, {
+			args:   []string{"import", "--conflict", "overwrite", "--identity", privFile, keyBundle},
+			status: exitOK,
+			entries: []diffEntry{
+				{Xname: "x0c0s1b0", Change: "overwritten", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "created"},
+			},
+			password: "secret-one",
+		},
+	}
+
+	for i, test := range tests {
+		args := append(test.args[:1:1], append([]string{"--format", "json"}, test.args[1:]...)...)
+		status, stdout, stderr := runCmd("", args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		var entries []diffEntry
+		json.Unmarshal([]byte(stdout), &entries)
+		if !reflect.DeepEqual(entries, test.entries) {
+			t.Errorf("Test %v Failed: Expected %v but got %v", i, test.entries, entries)
+		}
+		if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != test.password {
+			t.Errorf("Test %v Failed: Expected password %q but got %q", i, test.password, cred.Password)
+		}
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s2b0"); !reflect.DeepEqual(cred, testCreds[1]) {
+		t.Errorf("Expected x0c0s2b0 to be restored")
+	}
+}
+
+func TestExportImportShares(t *testing.T) {
+	ccs := setupStore(t)
+	dir := t.TempDir()
+	shareDir := filepath.Join(dir, "shares")
+	bundle := filepath.Join(dir, "creds.bundle")
+
+	if status, _, _ := runCmd("", "export", "--shares", "3", "--output", bundle); status != exitUsage {
+		t.Errorf("Expected --shares without --threshold to be refused but got status %v", status)
+	}
+	status, _, stderr := runCmd("", "export", "--shares", "3", "--threshold", "2", "--share-dir", shareDir, "--output", bundle)
+	if status != exitOK {
+		t.Fatalf("Expected export status %v but got %v: %s", exitOK, status, stderr)
+	}
+	for i := 1; i <= 3; i++ {
+		if fi, err := os.Stat(filepath.Join(shareDir, fmt.Sprintf("share-%d.txt", i))); err != nil || fi.Mode().Perm() != 0600 {
+			t.Errorf("Expected share %d with mode 0600 but got %v, %v", i, fi, err)
+		}
+	}
+	if status, _, _ := runCmd("", "export", "--shares", "3", "--threshold", "2", "--share-dir", shareDir, "--output", bundle); status != exitError {
+		t.Errorf("Expected existing shares not to be replaced but got status %v", status)
+	}
+
+	ccs.DeleteCompCred("x0c0s1b0")
+	share1 := filepath.Join(shareDir, "share-1.txt")
+	share3, _ := os.ReadFile(filepath.Join(shareDir, "share-3.txt"))
+
+	if status, _, _ := runCmd("", "import", "--share-file", share1, bundle); status != exitError {
+		t.Errorf("Expected one share to be refused but got status %v", status)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Xname != "" {
+		t.Errorf("Expected nothing to be imported with one share")
+	}
+	status, _, stderr = runCmd(string(share3), "import", "--share-file", share1, "--shares-stdin", bundle)
+	if status != exitOK {
+		t.Fatalf("Expected import status %v but got %v: %s", exitOK, status, stderr)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); !reflect.DeepEqual(cred, testCreds[0]) {
+		t.Errorf("Expected x0c0s1b0 to be restored but got %#v", cred)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	ccs := setupStore(t)
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	os.WriteFile(passphraseFile, []byte("correct horse\n"), 0600)
+	snapFile := filepath.Join(dir, "before.snapshot")
+
+	if status, _, stderr := runCmd("", "snapshot", "create", "--note", "campaign", "before"); status != exitOK {
+		t.Fatalf("Expected create status %v but got %v: %s", exitOK, status, stderr)
+	}
+	if status, _, stderr := runCmd("", "snapshot", "create", "--file", snapFile, "--passphrase-file", passphraseFile); status != exitOK {
+		t.Fatalf("Expected create status %v but got %v: %s", exitOK, status, stderr)
+	}
+	if status, _, _ := runCmd("", "snapshot", "create", "--file", snapFile); status != exitError {
+		t.Errorf("Expected a file snapshot without a key to be refused but got status %v", status)
+	}
+	status, stdout, _ := runCmd("", "snapshot", "list")
+	if status != exitOK || !strings.Contains(stdout, "before") || !strings.Contains(stdout, "campaign") {
+		t.Errorf("Expected the snapshot to be listed but got status %v: %s", status, stdout)
+	}
+
+	changed := testCreds[0]
+	changed.Password = "rotated"
+	ccs.StoreCompCred(changed)
+	ccs.DeleteCompCred("x0c0s2b0")
+
+	var tests = []struct {
+		args     []string
+		status   int
+		entries  []diffEntry
+		password string
+	}{
+		{
+			args:   []string{"snapshot", "diff", "--format", "json", "before"},
+			status: exitError,
+			entries: []diffEntry{
+				{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "removed"},
+			},
+			password: "rotated",
+		}, {
+			args:     []string{"snapshot", "restore", "--format", "json", "missing"},
+			status:   exitError,
+			password: "rotated",
+		}, {
+			args:     []string{"snapshot", "bogus"},
+			status:   exitUsage,
+			password: "rotated",
+		}, {
+			args:     []string{"snapshot", "restore", "--format", "json", "--dry-run", "--file", snapFile, "--passphrase-file", passphraseFile, "x0c0s1b0"},
+			status:   exitOK,
+			entries:  []diffEntry{{Xname: "x0c0s1b0", Change: "overwritten", Fields: []string{"password"}}},
+			password: "rotated",
+		}, {
+			args:     []string{"snapshot", "restore", "--format", "json", "before", "x0c0s2b0"},
+			status:   exitOK,
+			entries:  []diffEntry{{Xname: "x0c0s2b0", Change: "created"}},
+			password: "rotated",
+		}, {
+			args:   []string{"snapshot", "restore", "--format", "json", "--file", snapFile, "--passphrase-file", passphraseFile},
+			status: exitOK,
+			entries: []diffEntry{
+				{Xname: "x0c0s1b0", Change: "overwritten", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "unchanged"},
+			},
+			password: "secret-one",
+		},
+	}
+
+	for i, test := range tests {
+		status, stdout, stderr := runCmd("", test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		var entries []diffEntry
+		json.Unmarshal([]byte(stdout), &entries)
+		if !reflect.DeepEqual(entries, test.entries) {
+			t.Errorf("Test %v Failed: Expected %v but got %v", i, test.entries, entries)
+		}
+		if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != test.password {
+			t.Errorf("Test %v Failed: Expected password %q but got %q", i, test.password, cred.Password)
+		}
+	}
+
+	if status, _, _ := runCmd("", "snapshot", "delete", "before"); status != exitOK {
+		t.Errorf("Expected delete status %v but got %v", exitOK, status)
+	}
+	if _, stdout, _ := runCmd("", "snapshot", "list", "--format", "json"); strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("Expected no snapshots but got %s", stdout)
+	}
+}
+
+func TestCompareSync(t *testing.T) {
+	// Both stores share one backend, in different key spaces.
+	ss := conformance.NewMemoryStorage()
+	orig := newStore
+	newStore = func(opts *options) (*cc.CompCredStore, error) {
+		return cc.NewCompCredStore(opts.keyPath, ss), nil
+	}
+	t.Cleanup(func() { newStore = orig })
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	other := cc.NewCompCredStore("hms-creds-new", ss)
+	for _, cred := range testCreds {
+		ccs.StoreCompCred(cred)
+	}
+	changed := testCreds[0]
+	changed.Password = "rotated"
+	other.StoreCompCred(changed)
+	other.StoreCompCred(cc.CompCredentials{Xname: "x0c0s9b0", Password: "extra"})
+
+	var tests = []struct {
+		args    []string
+		status  int
+		results []cc.SyncResult
+	}{
+		{[]string{"compare"}, exitError, nil},
+		{
+			[]string{"compare", "--other-path", "hms-creds-new"},
+			exitError,
+			[]cc.SyncResult{
+				{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "removed"},
+				{Xname: "x0c0s9b0", Change: "added"},
+			},
+		}, {
+			[]string{"sync", "--other-path", "hms-creds-new", "--dry-run", "--delete"},
+			exitOK,
+			[]cc.SyncResult{
+				{Xname: "x0c0s1b0", Change: "updated", Fields: []string{"password"}},
+				{Xname: "x0c0s2b0", Change: "created"},
+				{Xname: "x0c0s9b0", Change: "deleted"},
+			},
+		}, {
+			[]string{"sync", "--other-path", "hms-creds-new", "--reverse", "x0c0s1b0"},
+			exitOK,
+			[]cc.SyncResult{{Xname: "x0c0s1b0", Change: "updated", Fields: []string{"password"}}},
+		}, {
+			[]string{"sync", "--other-path", "hms-creds-new"},
+			exitOK,
+			[]cc.SyncResult{{Xname: "x0c0s2b0", Change: "created"}, {Xname: "x0c0s9b0", Change: "skipped"}},
+		}, {
+			[]string{"compare", "--other-path", "hms-creds-new"},
+			exitError,
+			[]cc.SyncResult{{Xname: "x0c0s9b0", Change: "added"}},
+		},
+	}
+
+	for i, test := range tests {
+		args := append(test.args[:1:1], append([]string{"--format", "json"}, test.args[1:]...)...)
+		status, stdout, stderr := runCmd("", args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		var results []cc.SyncResult
+		json.Unmarshal([]byte(stdout), &results)
+		if !reflect.DeepEqual(results, test.results) {
+			t.Errorf("Test %v Failed: Expected %v but got %v", i, test.results, results)
+		}
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "rotated" {
+		t.Errorf("Expected --reverse to update this store but got %q", cred.Password)
+	}
+}
+
+func TestVaultAddr(t *testing.T) {
+	s := vaulttest.NewServer()
+	t.Cleanup(s.Close)
+	s.NewVaultAdapter(t, "secret")
+	// Only the explicit address reaches the server.
+	t.Setenv("VAULT_ADDR", "http://127.0.0.1:1")
+
+	ccs, err := newStore(&options{vaultAddr: s.URL, vaultBase: "secret", keyPath: cc.DefaultCompCredPath})
+	if err != nil {
+		t.Fatalf("newStore failed: %v", err)
+	}
+	if err := ccs.StoreCompCred(testCreds[0]); err != nil {
+		t.Fatalf("StoreCompCred failed: %v", err)
+	}
+	if _, ok := s.Data("secret/hms-creds/x0c0s1b0"); !ok {
+		t.Errorf("Expected the credentials to be stored in the Vault at --vault-addr")
+	}
+	if os.Getenv("VAULT_ADDR") != "http://127.0.0.1:1" {
+		t.Errorf("Expected VAULT_ADDR to be left alone")
+	}
+
+	status, _, _ := runCmd("", "compare", "--vault-addr", s.URL, "--other-vault-addr", s.URL)
+	if status != exitError {
+		t.Errorf("Expected a store compared with itself to be refused but got status %v", status)
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	ccs := setupStore(t)
+	spec := filepath.Join(t.TempDir(), "creds.yaml")
+	os.WriteFile(spec, []byte(`version: 1
+prune: true
+components:
+- xname: x0c0s1b0
+  username: admin
+  password: {ref: "env:TEST_PLAN_PASSWORD"}
+- xname: x0c0s3b0
+  username: root
+  password: {generate: true}
+`), 0600)
+	t.Setenv("TEST_PLAN_PASSWORD", "from-env")
+
+	expected := []desired.Change{
+		{Xname: "x0c0s1b0", Action: "update", Fields: []string{"username", "password"}},
+		{Xname: "x0c0s2b0", Action: "delete"},
+		{Xname: "x0c0s3b0", Action: "create", Fields: []string{"username"}, Generate: []string{"password"}},
+	}
+	var tests = []struct {
+		args    []string
+		status  int
+		changes []desired.Change
+	}{
+		{[]string{"plan", "--format", "json", spec}, exitError, expected},
+		{[]string{"apply", "--format", "json", spec}, exitOK, expected},
+		{[]string{"plan", "--format", "json", spec}, exitOK, []desired.Change{}},
+		{[]string{"plan"}, exitUsage, nil},
+		{[]string{"apply", filepath.Join(t.TempDir(), "missing.yaml")}, exitError, nil},
+	}
+	for i, test := range tests {
+		status, stdout, stderr := runCmd("", test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		if strings.Contains(stdout, "from-env") {
+			t.Errorf("Test %v Failed: Secret value printed: %s", i, stdout)
+		}
+		var changes []desired.Change
+		json.Unmarshal([]byte(stdout), &changes)
+		if !reflect.DeepEqual(changes, test.changes) {
+			t.Errorf("Test %v Failed: Expected %+v but got %+v", i, test.changes, changes)
+		}
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "from-env" || cred.URL != testCreds[0].URL {
+		t.Errorf("Unexpected credentials after apply: %v", cred)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s3b0"); cred.Password == "" {
+		t.Errorf("Expected a generated password for x0c0s3b0")
+	}
+
+	status, stdout, _ := runCmd("", "plan", spec)
+	if status != exitOK || !strings.HasPrefix(stdout, "XNAME") {
+		t.Errorf("Unexpected table output %q with status %v", stdout, status)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	orig := newStore
+	newStore = func(opts *options) (*cc.CompCredStore, error) {
+		return cc.NewCompCredStore(opts.keyPath, ss), nil
+	}
+	t.Cleanup(func() { newStore = orig })
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	ccs.StoreCompCred(testCreds[0])
+	ss.Store(cc.DefaultCompCredPath+"/x0c0s2b0", map[string]interface{}{"Xname": "x0c0s2b0", "Passwd": "old"})
+
+	var tests = []struct {
+		args    []string
+		status  int
+		results []cc.MigrateResult
+	}{
+		{[]string{"migrate"}, exitError, nil},
+		{[]string{"migrate", "--rename-field", "Passwd"}, exitUsage, nil},
+		{
+			[]string{"migrate", "--other-path", "hms-creds-v2", "--rename-field", "Passwd=Password", "--dry-run", "--delete-source"},
+			exitOK,
+			[]cc.MigrateResult{
+				{Xname: "x0c0s1b0", Change: "migrated", Deleted: true},
+				{Xname: "x0c0s2b0", Change: "migrated", Deleted: true},
+			},
+		}, {
+			[]string{"migrate", "--other-path", "hms-creds-v2", "--rename-field", "Passwd=Password", "--delete-source"},
+			exitOK,
+			[]cc.MigrateResult{
+				{Xname: "x0c0s1b0", Change: "migrated", Deleted: true},
+				{Xname: "x0c0s2b0", Change: "migrated", Deleted: true},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		args := append(test.args[:1:1], append([]string{"--format", "json"}, test.args[1:]...)...)
+		status, stdout, stderr := runCmd("", args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		var results []cc.MigrateResult
+		json.Unmarshal([]byte(stdout), &results)
+		if !reflect.DeepEqual(results, test.results) {
+			t.Errorf("Test %v Failed: Expected %v but got %v", i, test.results, results)
+		}
+	}
+	migrated := cc.NewCompCredStore("hms-creds-v2", ss)
+	if cred, _ := migrated.GetCompCred("x0c0s2b0"); cred.Password != "old" {
+		t.Errorf("Expected the renamed field to be migrated but got %#v", cred)
+	}
+	if keys, _ := ss.LookupKeys(cc.DefaultCompCredPath); len(keys) != 0 {
+		t.Errorf("Expected the source to be deleted but got %v", keys)
+	}
+}
+
+func TestFallbackPath(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	orig := newStore
+	newStore = func(opts *options) (*cc.CompCredStore, error) {
+		return cc.NewCompCredStore(opts.keyPath, ss), nil
+	}
+	t.Cleanup(func() { newStore = orig })
+	cc.NewCompCredStore(cc.DefaultCompCredPath, ss).StoreCompCred(testCreds[0])
+
+	var tests = []struct {
+		args   []string
+		status int
+		xnames int
+	}{
+		{[]string{"list", "--path", "hms-creds-v2", "--migrate-on-read"}, exitUsage, 0},
+		{[]string{"get", "--path", "hms-creds-v2", "x0c0s1b0"}, exitError, 0},
+		{[]string{"list", "--path", "hms-creds-v2", "--fallback-path", "hms-creds"}, exitOK, 1},
+		{[]string{"get", "--path", "hms-creds-v2", "--fallback-path", "hms-creds", "--migrate-on-read", "x0c0s1b0"}, exitOK, 1},
+		{[]string{"get", "--path", "hms-creds-v2", "x0c0s1b0"}, exitOK, 1},
+	}
+	for i, test := range tests {
+		args := append(test.args[:1:1], append([]string{"--format", "json"}, test.args[1:]...)...)
+		status, stdout, stderr := runCmd("", args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		if n := strings.Count(stdout, `"xname"`); n != test.xnames {
+			t.Errorf("Test %v Failed: Expected %v credentials but got %s", i, test.xnames, stdout)
+		}
+	}
+}
+
+func TestHistoryRollback(t *testing.T) {
+	ccs := setupStore(t)
+	ccs.HistoryLimit = 3
+	for _, password := range []string{"rotated-once", "rotated-twice"} {
+		cred := testCreds[0]
+		cred.Password = password
+		ccs.StoreCompCred(cred)
+	}
+
+	var tests = []struct {
+		args   []string
+		status int
+		stdout string
+	}{
+		{[]string{"history", "x0c0s1b0"}, exitError, ""},
+		{[]string{"history", "--kv-version", "3", "x0c0s1b0"}, exitUsage, ""},
+		{[]string{"history", "--history-limit", "3", "--format", "json", "x0c0s1b0"}, exitOK, `"version": 3`},
+		{[]string{"get", "--history-limit", "3", "--version", "1", "--show-secrets", "x0c0s1b0"}, exitOK, "secret-one"},
+		{[]string{"rollback", "--history-limit", "3", "x0c0s1b0", "zero"}, exitUsage, ""},
+		{[]string{"rollback", "--history-limit", "3", "x0c0s1b0", "9"}, exitError, ""},
+		{[]string{"rollback", "--history-limit", "3", "x0c0s1b0", "1"}, exitOK, ""},
+		{[]string{"history", "--history-limit", "3", "x0c0s1b0"}, exitOK, "4 "},
+	}
+	for i, test := range tests {
+		status, stdout, stderr := runCmd("", test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		if !strings.Contains(stdout, test.stdout) {
+			t.Errorf("Test %v Failed: Expected output containing %q but got %q", i, test.stdout, stdout)
+		}
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "secret-one" {
+		t.Errorf("Expected the rolled back password but got %q", cred.Password)
+	}
+}
+
+func TestPasswordReuseFlags(t *testing.T) {
+	setupStore(t)
+	keyFile := filepath.Join(t.TempDir(), "reuse.key")
+	os.WriteFile(keyFile, []byte(strings.Repeat("k", cc.MinReuseKeyLength)+"\n"), 0600)
+	reuse := []string{"--reuse-remember", "3", "--reuse-key-file", keyFile}
+
+	var tests = []struct {
+		args   []string
+		stdin  string
+		status int
+	}{
+		{[]string{"set", "--reuse-remember", "3", "--password-stdin", "x0c0s1b0"}, "new-one\n", exitUsage},
+		{[]string{"set", "--reuse-remember", "3", "--reuse-key-file", filepath.Join(t.TempDir(), "missing"), "--password-stdin", "x0c0s1b0"}, "new-one\n", exitError},
+		{append(append([]string{"set"}, reuse...), "--password-stdin", "x0c0s1b0"), "new-one\n", exitOK},
+		{append(append([]string{"set"}, reuse...), "--password-stdin", "x0c0s1b0"), "secret-one\n", exitError},
+		{[]string{"set", "--password-stdin", "x0c0s1b0"}, "secret-one\n", exitOK},
+	}
+	for i, test := range tests {
+		status, _, stderr := runCmd(test.stdin, test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		if strings.Contains(stderr, "secret-one") {
+			t.Errorf("Test %v Failed: Password in error %s", i, stderr)
+		}
+	}
+
+	// Importing an export is a restore, so its passwords are not refused,
+	// as with a bundle.
+	exportFile := filepath.Join(t.TempDir(), "creds.json")
+	if status, _, stderr := runCmd("", "export", "--show-secrets", "--output", exportFile); status != exitOK {
+		t.Fatalf("Export failed: %s", stderr)
+	}
+	if status, _, stderr := runCmd("rotated\n", append(append([]string{"set"}, reuse...), "--password-stdin", "x0c0s1b0")...); status != exitOK {
+		t.Fatalf("Set failed: %s", stderr)
+	}
+	if status, _, stderr := runCmd("", append(append([]string{"import"}, reuse...), "--overwrite", exportFile)...); status != exitOK {
+		t.Errorf("Expected the import not to be refused but got status %v: %s", status, stderr)
+	}
+}
+
+func TestMetadataDue(t *testing.T) {
+	ccs := setupStore(t)
+
+	var tests = []struct {
+		args     []string
+		status   int
+		contains []string
+		excludes []string
+	}{
+		{[]string{"set", "--expire-after", "24h", "--url", "10.4.0.99", "x0c0s1b0"}, exitUsage, nil, nil},
+		{[]string{"set", "--expires", "tomorrow", "--url", "10.4.0.99", "x0c0s1b0"}, exitError, nil, nil},
+		{[]string{"set", "--updater", "alice", "--expire-after", "24h", "--url", "10.4.0.99", "x0c0s1b0"}, exitOK, nil, nil},
+		{[]string{"set", "--expires", "2020-01-01T00:00:00Z", "x0c0s2b0"}, exitOK, nil, nil},
+		{[]string{"due"}, exitError, []string{"x0c0s2b0", "expired"}, []string{"x0c0s1b0"}},
+		{[]string{"due", "--max-age", "2160h", "--format", "json"}, exitError, []string{`"xname": "x0c0s2b0"`}, []string{`"xname": "x0c0s1b0"`, "secret"}},
+	}
+	for i, test := range tests {
+		status, stdout, stderr := runCmd("", test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, status, stderr)
+		}
+		for _, s := range test.contains {
+			if !strings.Contains(stdout, s) {
+				t.Errorf("Test %v Failed: Expected %q in output %s", i, s, stdout)
+			}
+		}
+		for _, s := range test.excludes {
+			if strings.Contains(stdout, s) {
+				t.Errorf("Test %v Failed: Unexpected %q in output %s", i, s, stdout)
+			}
+		}
+	}
+
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.UpdatedBy != "alice" || cred.Expires == "" {
+		t.Errorf("Expected the change to be recorded for alice but got %q, %q", cred.UpdatedBy, cred.Expires)
+	}
+}
+
+func TestAgentFlags(t *testing.T) {
+	setupStore(t)
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	os.WriteFile(rulesFile, []byte(`{"not": "a list"}`), 0600)
+
+	var tests = []struct {
+		args   []string
+		status int
+	}{
+		{[]string{"agent"}, exitUsage},
+		{[]string{"agent", "--rules", filepath.Join(t.TempDir(), "missing.json")}, exitError},
+		{[]string{"agent", "--rules", rulesFile}, exitError},
+	}
+	for i, test := range tests {
+		if status, _, _ := runCmd("", test.args...); status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v", i, test.status, status)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	setupStore(t)
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "t.tmpl")
+	dest := filepath.Join(dir, "out.conf")
+	config := filepath.Join(dir, "render.json")
+	os.WriteFile(tmpl, []byte("{{range .Xnames}}{{.}}\n{{end}}"), 0600)
+	os.WriteFile(config, []byte(`{"templates":[{"source":"`+tmpl+`","dest":"`+dest+`"}]}`), 0600)
+
+	if status, _, stderr := runCmd("", "render", "--once", "--config", config); status != exitOK {
+		t.Fatalf("Expected status %v but got %v: %s", exitOK, status, stderr)
+	}
+	if buf, _ := os.ReadFile(dest); string(buf) != "x0c0s1b0\nx0c0s2b0\n" {
+		t.Errorf("Unexpected rendered content %q", buf)
+	}
+	if status, _, _ := runCmd("", "render", "--once"); status != exitUsage {
+		t.Errorf("Expected status %v without --config but got %v", exitUsage, status)
+	}
+}
+
+func TestExec(t *testing.T) {
+	setupStore(t)
+
+	var tests = []struct {
+		args   []string
+		status int
+		stdout string
+	}{
+		{
+			args:   []string{"exec", "--xname", "x0c0s1b0", "--", "sh", "-c", `printf %s "$COMPCRED_PASSWORD"`},
+			status: 0,
+			stdout: "secret-one",
+		}, {
+			args:   []string{"exec", "--xname", "x0c0s1b0", "--password-env", "IPMI_PASSWORD", "--", "sh", "-c", `printf %s "$IPMI_PASSWORD"; exit 4`},
+			status: 4,
+			stdout: "secret-one",
+		}, {
+			args:   []string{"exec", "--xname", "x0c0s1b0", "--username-env=", "--password-env=", "--url-env=", "--", "sh", "-c", `env | grep -c '^COMPCRED_'; true`},
+			status: 0,
+			stdout: "0\n",
+		}, {
+			args:   []string{"exec", "--", "true"},
+			status: exitUsage,
+		}, {
+			args:   []string{"exec", "--xname", "x9999c0s0b0", "--", "true"},
+			status: exitError,
+		},
+	}
+	for i, test := range tests {
+		status, stdout, _ := runCmd("", test.args...)
+		if status != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v", i, test.status, status)
+		}
+		if stdout != test.stdout {
+			t.Errorf("Test %v Failed: Expected output %q but got %q", i, test.stdout, stdout)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ccs := setupStore(t)
+	bmc := redfishtest.NewServer()
+	defer bmc.Close()
+	bmc.AddAccount("test1", "secret-one", "Administrator")
+	if err := ccs.StoreCompCred(cc.CompCredentials{
+		Xname: "x0c0s1b0", URL: bmc.URL + "/redfish/v1/", Username: "test1", Password: "secret-one",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, stdout, _ := runCmd("", "verify", "x0c0s1b0")
+	if status != exitOK || !strings.Contains(stdout, "x0c0s1b0") || !strings.Contains(stdout, "1 ok") {
+		t.Errorf("Expected x0c0s1b0 to verify but got status %v and output:\n%s", status, stdout)
+	}
+
+	bmc.SetPassword("test1", "rotated")
+	status, stdout, _ = runCmd("", "verify", "--format", "json", "x0c0s1b0")
+	if status != exitError || !strings.Contains(stdout, `"status": "auth_failed"`) {
+		t.Errorf("Expected an auth failure but got status %v and output:\n%s", status, stdout)
+	}
+}
+
+func TestPasswd(t *testing.T) {
+	ccs := setupStore(t)
+	bmc := redfishtest.NewServer()
+	defer bmc.Close()
+	bmc.AddAccount("test1", "secret-one", "Administrator")
+	if err := ccs.StoreCompCred(cc.CompCredentials{
+		Xname: "x0c0s1b0", URL: bmc.URL + "/redfish/v1", Username: "test1", Password: "secret-one",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if status, _, _ := runCmd("", "passwd", "x0c0s1b0"); status != exitUsage {
+		t.Errorf("Expected status %v without a password but got %v", exitUsage, status)
+	}
+	if status, _, stderr := runCmd("new-secret\n", "passwd", "--password-stdin", "x0c0s1b0"); status != exitOK {
+		t.Fatalf("Expected status %v but got %v: %s", exitOK, status, stderr)
+	}
+	acct, _ := bmc.Account("test1")
+	cred, _ := ccs.GetCompCred("x0c0s1b0")
+	if acct.Password != "new-secret" || cred.Password != "new-secret" {
+		t.Errorf("Expected the BMC and store to have the new password but got %q and %q", acct.Password, cred.Password)
+	}
+
+	if status, _, _ := runCmd("new-secret\n", "passwd", "--password-stdin", "--generate", "x0c0s1b0"); status != exitUsage {
+		t.Errorf("Expected status %v with two password sources but got %v", exitUsage, status)
+	}
+	bmc.SetPasswordPolicy(18, 18)
+	status, stdout, stderr := runCmd("", "passwd", "--generate", "--show-secrets", "x0c0s1b0")
+	if status != exitOK {
+		t.Fatalf("Expected status %v but got %v: %s", exitOK, status, stderr)
+	}
+	acct, _ = bmc.Account("test1")
+	cred, _ = ccs.GetCompCred("x0c0s1b0")
+	if len(acct.Password) != 18 || cred.Password != acct.Password || stdout != acct.Password+"\n" {
+		t.Errorf("Expected an 18 character generated password on the BMC, in the store and printed, but got %q, %q and %q",
+			acct.Password, cred.Password, stdout)
+	}
+}
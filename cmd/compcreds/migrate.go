@@ -0,0 +1,88 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+func (c *cli) migrate(name string, args []string) int {
+	var (
+		opts, other options
+		migrateOpts cc.MigrateOptions
+	)
+	renames := make(map[string]string)
+	fs := c.flagSet(name, &opts)
+	otherStoreFlags(fs, &other)
+	fs.StringVar(&migrateOpts.Checkpoint, "checkpoint", "", "Record progress under this name so an interrupted migration can be resumed")
+	fs.BoolVar(&migrateOpts.DeleteSource, "delete-source", false, "Remove the migrated credentials from this store once all are migrated")
+	fs.BoolVar(&migrateOpts.DryRun, "dry-run", false, "Show what would be migrated without changing either store")
+	fs.Func("rename-field", "Rename a stored field as OLD=NEW, such as to upgrade an older schema; repeat for each field", func(s string) error {
+		from, to, ok := strings.Cut(s, "=")
+		if !ok || from == "" || to == "" {
+			return fmt.Errorf("expected OLD=NEW")
+		}
+		renames[from] = to
+		return nil
+	})
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if len(renames) > 0 {
+		migrateOpts.Transforms = append(migrateOpts.Transforms, cc.RenameFields(renames))
+	}
+	src, dst, ok := c.storePair(&opts, &other)
+	if !ok {
+		return exitError
+	}
+
+	results, migrateErr := cc.Migrate(src, dst, migrateOpts)
+	if opts.format == "json" {
+		if results == nil {
+			results = []cc.MigrateResult{}
+		}
+		if err := writeJSON(c.stdout, results); err != nil {
+			return c.errorf("%v", err)
+		}
+	} else if err := c.printMigrateResults(results); err != nil {
+		return c.errorf("%v", err)
+	}
+	if migrateErr != nil {
+		return c.errorf("%v", migrateErr)
+	}
+	return exitOK
+}
+
+func (c *cli) printMigrateResults(results []cc.MigrateResult) error {
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tTO\tCHANGE\tDELETED\tERROR")
+	for _, res := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\n", res.Xname, res.To, res.Change, res.Deleted, res.Error)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,93 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Write credentials in the requested format, redacting secrets unless asked
+// not to.
+func (c *cli) printCreds(opts *options, creds map[string]cc.CompCredentials) error {
+	xnames := sortedXnames(creds)
+	shown := make([]cc.CompCredentials, 0, len(xnames))
+	for _, xname := range xnames {
+		cred := creds[xname]
+		if !opts.showSecrets {
+			cred = cred.Redacted()
+		}
+		shown = append(shown, cred)
+	}
+
+	if opts.format == "json" {
+		return writeJSON(c.stdout, shown)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tUSERNAME\tPASSWORD\tSNMP AUTH\tSNMP PRIV\tURL")
+	for _, cred := range shown {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", cred.Xname, cred.Username,
+			cred.Password, cred.SNMPAuthPass, cred.SNMPPrivPass, cred.URL)
+	}
+	return tw.Flush()
+}
+
+type diffEntry struct {
+	Xname  string   `json:"xname"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+func (c *cli) printDiff(opts *options, entries []diffEntry) error {
+	if opts.format == "json" {
+		return writeJSON(c.stdout, entries)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tCHANGE\tFIELDS")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Xname, e.Change, strings.Join(e.Fields, ","))
+	}
+	return tw.Flush()
+}
+
+func (c *cli) printSyncResults(results []cc.SyncResult) error {
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tCHANGE\tFIELDS\tERROR")
+	for _, res := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.Xname, res.Change, strings.Join(res.Fields, ","), res.Error)
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
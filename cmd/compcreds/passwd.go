@@ -0,0 +1,108 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Cray-HPE/hms-compcredentials/redfish"
+)
+
+func (c *cli) passwd(name string, args []string) int {
+	var (
+		opts          options
+		passwordFile  string
+		passwordStdin bool
+		generate      bool
+		timeout       time.Duration
+		caFile        string
+		insecure      bool
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&passwordFile, "password-file", "", "Read the new password from this file")
+	fs.BoolVar(&passwordStdin, "password-stdin", false, "Read the new password from the first line of standard input")
+	fs.BoolVar(&generate, "generate", false, "Generate a password the BMC's password policy accepts")
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for each request")
+	fs.StringVar(&caFile, "ca-cert", "", "PEM file of CA certificates BMCs are verified against")
+	fs.BoolVar(&insecure, "insecure", false, "Do not verify BMC certificates")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	sources := 0
+	for _, set := range []bool{passwordFile != "", passwordStdin, generate} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		fmt.Fprintf(c.stderr, "compcreds: exactly one of --password-file, --password-stdin and --generate is required\n")
+		return exitUsage
+	}
+	xname := fs.Arg(0)
+
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+	client, err := newHTTPClient(caFile, insecure, timeout)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	am := redfish.NewAccountManager(ccs)
+	am.Client = client
+
+	var password string
+	switch {
+	case generate:
+		if password, err = am.GeneratePassword(context.Background(), xname); err != nil {
+			return c.errorf("%v", err)
+		}
+	case passwordFile != "":
+		if password, err = readSecretFile(passwordFile); err != nil {
+			return c.errorf("%v", err)
+		}
+	default:
+		line, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return c.errorf("unable to read password from standard input: %v", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		return c.errorf("the new password is empty")
+	}
+
+	if err := am.SetPassword(context.Background(), xname, password); err != nil {
+		return c.errorf("%v", err)
+	}
+	fmt.Fprintf(c.stderr, "Changed the password for %s on the BMC and in the store\n", xname)
+	if generate && opts.showSecrets {
+		fmt.Fprintln(c.stdout, password)
+	}
+	return exitOK
+}
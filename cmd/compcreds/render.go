@@ -0,0 +1,78 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Cray-HPE/hms-compcredentials/render"
+)
+
+func (c *cli) render(name string, args []string) int {
+	var (
+		opts       options
+		configFile string
+		once       bool
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&configFile, "config", "", "JSON file listing the templates to render (required)")
+	fs.BoolVar(&once, "once", false, "Render once and exit instead of re-rendering on change")
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 || configFile == "" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	interval, templates, err := render.LoadConfig(configFile)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+	r := render.NewRenderer(ccs, templates...)
+	r.Interval = interval
+
+	if once {
+		results, err := r.RenderOnce()
+		for _, res := range results {
+			if res.Err != nil {
+				c.errorf("%s: %v", res.Dest, res.Err)
+			}
+		}
+		if err != nil {
+			return exitError
+		}
+		return exitOK
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := r.Run(ctx); err != nil {
+		return c.errorf("%v", err)
+	}
+	return exitOK
+}
@@ -0,0 +1,230 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+func (c *cli) snapshot(name string, args []string) int {
+	var (
+		opts           options
+		note           string
+		file           string
+		passphraseFile string
+		recipient      string
+		identity       string
+		dryRun         bool
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&note, "note", "", "With create, a note saying why the snapshot was taken")
+	fs.StringVar(&file, "file", "", "Write or read the snapshot as an encrypted file instead of in the store")
+	fs.StringVar(&passphraseFile, "passphrase-file", "", "With --file, the file holding the passphrase")
+	fs.StringVar(&recipient, "recipient", "", "With create --file, the X25519 public key PEM file to encrypt for")
+	fs.StringVar(&identity, "identity", "", "With --file, the X25519 private key PEM file to decrypt with")
+	fs.BoolVar(&dryRun, "dry-run", false, "With restore, show what would be stored without storing it")
+	if len(args) == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	sub := args[0]
+	if !c.parse(fs, &opts, args[1:]) {
+		fs.Usage()
+		return exitUsage
+	}
+
+	// Snapshots in the store are named by the first argument; those in a
+	// file are not.
+	rest := fs.Args()
+	var snapName string
+	switch sub {
+	case "create":
+		if len(rest) > 1 {
+			fs.Usage()
+			return exitUsage
+		}
+		if len(rest) == 1 {
+			snapName = rest[0]
+		}
+	case "list":
+		if len(rest) != 0 || file != "" {
+			fs.Usage()
+			return exitUsage
+		}
+	case "diff", "restore", "delete":
+		if file == "" {
+			if len(rest) == 0 {
+				fs.Usage()
+				return exitUsage
+			}
+			snapName, rest = rest[0], rest[1:]
+		}
+		if (sub == "diff" || sub == "delete") && len(rest) != 0 || sub == "delete" && file != "" {
+			fs.Usage()
+			return exitUsage
+		}
+	default:
+		fmt.Fprintf(c.stderr, "compcreds: unknown snapshot command %q\n", sub)
+		fs.Usage()
+		return exitUsage
+	}
+
+	var key cc.BundleKey
+	if file != "" {
+		keyFile := identity
+		if sub == "create" {
+			keyFile = recipient
+		}
+		k, given, err := readBundleKey(passphraseFile, keyFile, sub != "create")
+		if err != nil {
+			return c.errorf("%v", err)
+		}
+		if !given {
+			return c.errorf("--file needs --passphrase-file or a key")
+		}
+		key = k
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	switch sub {
+	case "create":
+		return c.snapshotCreate(ccs, snapName, note, file, key)
+	case "list":
+		infos, err := ccs.ListSnapshots()
+		if err != nil {
+			return c.errorf("unable to list snapshots: %v", err)
+		}
+		if err := c.printSnapshots(&opts, infos); err != nil {
+			return c.errorf("%v", err)
+		}
+		return exitOK
+	case "delete":
+		if err := ccs.DeleteSnapshot(snapName); err != nil {
+			return c.errorf("unable to delete snapshot %s: %v", snapName, err)
+		}
+		return exitOK
+	}
+
+	s, err := c.loadSnapshot(ccs, snapName, file, key)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if sub == "diff" {
+		diffs, err := ccs.DiffSnapshot(s)
+		if err != nil {
+			return c.errorf("%v", err)
+		}
+		entries := make([]diffEntry, len(diffs))
+		for i, d := range diffs {
+			entries[i] = diffEntry{Xname: d.Xname, Change: d.Change, Fields: d.Fields}
+		}
+		if err := c.printDiff(&opts, entries); err != nil {
+			return c.errorf("%v", err)
+		}
+		if len(entries) > 0 {
+			return exitError
+		}
+		return exitOK
+	}
+
+	changes, restoreErr := ccs.RestoreSnapshot(s, rest, dryRun)
+	entries := make([]diffEntry, len(changes))
+	for i, change := range changes {
+		entries[i] = diffEntry{Xname: change.Xname, Change: change.Change, Fields: change.Fields}
+	}
+	if len(entries) > 0 {
+		if err := c.printDiff(&opts, entries); err != nil {
+			return c.errorf("%v", err)
+		}
+	}
+	if restoreErr != nil {
+		return c.errorf("%v", restoreErr)
+	}
+	return exitOK
+}
+
+func (c *cli) snapshotCreate(ccs *cc.CompCredStore, name, note, file string, key cc.BundleKey) int {
+	if file == "" {
+		info, err := ccs.TakeSnapshot(name, note)
+		if err != nil {
+			return c.errorf("unable to take snapshot: %v", err)
+		}
+		fmt.Fprintf(c.stderr, "Took snapshot %s of %d credentials\n", info.Name, info.Count)
+		return exitOK
+	}
+
+	s, err := ccs.CurrentSnapshot(name, note)
+	if err != nil {
+		return c.errorf("unable to take snapshot: %v", err)
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	err = cc.WriteSnapshot(f, s, key)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(file)
+		return c.errorf("unable to write snapshot: %v", err)
+	}
+	fmt.Fprintf(c.stderr, "Wrote a snapshot of %d credentials to %s\n", s.Count, file)
+	return exitOK
+}
+
+func (c *cli) loadSnapshot(ccs *cc.CompCredStore, name, file string, key cc.BundleKey) (cc.Snapshot, error) {
+	if file == "" {
+		return ccs.GetSnapshot(name)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return cc.Snapshot{}, err
+	}
+	defer f.Close()
+	return cc.ReadSnapshot(f, key)
+}
+
+func (c *cli) printSnapshots(opts *options, infos []cc.SnapshotInfo) error {
+	if opts.format == "json" {
+		if infos == nil {
+			infos = []cc.SnapshotInfo{}
+		}
+		return writeJSON(c.stdout, infos)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCREATED\tCOUNT\tNOTE")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", info.Name, info.Created.Format(time.RFC3339), info.Count, info.Note)
+	}
+	return tw.Flush()
+}
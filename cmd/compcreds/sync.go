@@ -0,0 +1,127 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Add the flags that select the other store of compare and sync.
+func otherStoreFlags(fs *flag.FlagSet, other *options) {
+	fs.StringVar(&other.vaultAddr, "other-vault-addr", "", "Address of the other store's Vault; defaults to VAULT_ADDR")
+	fs.StringVar(&other.vaultBase, "other-vault-base", sstorage.DefaultBasePath, "Vault secrets engine path of the other store")
+	fs.StringVar(&other.keyPath, "other-path", cc.DefaultCompCredPath, "Key space of the other store")
+}
+
+// Open this store and the other one, refusing to use one store as both.
+func (c *cli) storePair(opts, other *options) (*cc.CompCredStore, *cc.CompCredStore, bool) {
+	if other.vaultAddr == opts.vaultAddr && other.vaultBase == opts.vaultBase && other.keyPath == opts.keyPath {
+		fmt.Fprintf(c.stderr, "compcreds: the other store is this store; give --other-vault-addr, --other-vault-base or --other-path\n")
+		return nil, nil, false
+	}
+	ccs, ok := c.store(opts)
+	if !ok {
+		return nil, nil, false
+	}
+	otherCCS, ok := c.store(other)
+	if !ok {
+		return nil, nil, false
+	}
+	return ccs, otherCCS, true
+}
+
+func (c *cli) compare(name string, args []string) int {
+	var opts, other options
+	fs := c.flagSet(name, &opts)
+	otherStoreFlags(fs, &other)
+	if !c.parse(fs, &opts, args) || fs.NArg() != 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	ccs, otherCCS, ok := c.storePair(&opts, &other)
+	if !ok {
+		return exitError
+	}
+
+	diffs, err := cc.Diff(ccs, otherCCS)
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	entries := make([]diffEntry, len(diffs))
+	for i, d := range diffs {
+		entries[i] = diffEntry{Xname: d.Xname, Change: d.Change, Fields: d.Fields}
+	}
+	if err := c.printDiff(&opts, entries); err != nil {
+		return c.errorf("%v", err)
+	}
+	if len(entries) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+func (c *cli) sync(name string, args []string) int {
+	var (
+		opts, other options
+		syncOpts    cc.SyncOptions
+		reverse     bool
+	)
+	fs := c.flagSet(name, &opts)
+	otherStoreFlags(fs, &other)
+	fs.BoolVar(&reverse, "reverse", false, "Make these credentials match the other store's instead")
+	fs.BoolVar(&syncOpts.Delete, "delete", false, "Remove credentials the source does not have")
+	fs.BoolVar(&syncOpts.DryRun, "dry-run", false, "Show what would be changed without changing it")
+	if !c.parse(fs, &opts, args) {
+		fs.Usage()
+		return exitUsage
+	}
+	syncOpts.Xnames = fs.Args()
+	src, dst, ok := c.storePair(&opts, &other)
+	if !ok {
+		return exitError
+	}
+	if reverse {
+		src, dst = dst, src
+	}
+
+	results, syncErr := cc.Sync(src, dst, syncOpts)
+	if opts.format == "json" {
+		if results == nil {
+			results = []cc.SyncResult{}
+		}
+		if err := writeJSON(c.stdout, results); err != nil {
+			return c.errorf("%v", err)
+		}
+	} else if err := c.printSyncResults(results); err != nil {
+		return c.errorf("%v", err)
+	}
+	if syncErr != nil {
+		return c.errorf("%v", syncErr)
+	}
+	return exitOK
+}
@@ -0,0 +1,143 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Cray-HPE/hms-compcredentials/verify"
+)
+
+func (c *cli) verify(name string, args []string) int {
+	var (
+		opts     options
+		s        verify.Scanner
+		cabRate  float64
+		timeout  time.Duration
+		caFile   string
+		insecure bool
+		protocol string
+		unpinned bool
+		snmpAuth string
+		snmpPriv string
+	)
+	fs := c.flagSet(name, &opts)
+	fs.StringVar(&protocol, "protocol", "redfish", "How components are checked: redfish, ssh or snmp")
+	fs.StringVar(&s.Path, "redfish-path", "", "Path checked on each BMC instead of the stored URL, e.g. /redfish/v1/Managers")
+	fs.IntVar(&s.Concurrency, "concurrency", verify.DefaultConcurrency, "Components checked at once")
+	fs.Float64Var(&cabRate, "cabinet-rate", 0, "Requests per second allowed to each cabinet; 0 for no limit")
+	fs.IntVar(&s.CabinetBurst, "cabinet-burst", 1, "Requests allowed to each cabinet in a burst")
+	fs.DurationVar(&timeout, "timeout", verify.DefaultTimeout, "Timeout for each request")
+	fs.StringVar(&caFile, "ca-cert", "", "PEM file of CA certificates BMCs are verified against")
+	fs.BoolVar(&insecure, "insecure", false, "Do not verify BMC certificates")
+	fs.BoolVar(&unpinned, "allow-unpinned", false, "With ssh, accept hosts with no pinned host key")
+	fs.StringVar(&snmpAuth, "snmp-auth-protocol", "SHA", "With snmp, the authentication protocol: MD5, SHA, SHA224, SHA256, SHA384 or SHA512")
+	fs.StringVar(&snmpPriv, "snmp-priv-protocol", "AES", "With snmp, the privacy protocol: DES, AES, AES192, AES256, AES192C or AES256C")
+	if !c.parse(fs, &opts, args) {
+		fs.Usage()
+		return exitUsage
+	}
+	if protocol != "redfish" && protocol != "ssh" && protocol != "snmp" {
+		fmt.Fprintf(c.stderr, "compcreds: unknown protocol %q\n", protocol)
+		return exitUsage
+	}
+	ccs, ok := c.store(&opts)
+	if !ok {
+		return exitError
+	}
+
+	var scanner interface {
+		Scan(ctx context.Context, xnames []string) (verify.Report, error)
+	}
+	switch protocol {
+	case "redfish":
+		client, err := newHTTPClient(caFile, insecure, timeout)
+		if err != nil {
+			return c.errorf("%v", err)
+		}
+		s.Client = client
+		s.Store = ccs
+		s.CabinetRate = rate.Limit(cabRate)
+		scanner = &s
+	case "ssh":
+		scanner = &verify.SSHScanner{
+			Store:         ccs,
+			Concurrency:   s.Concurrency,
+			Timeout:       timeout,
+			AllowUnpinned: unpinned,
+		}
+	case "snmp":
+		scanner = &verify.SNMPScanner{
+			Store:        ccs,
+			AuthProtocol: snmpAuth,
+			PrivProtocol: snmpPriv,
+			Concurrency:  s.Concurrency,
+			Timeout:      timeout,
+		}
+	}
+
+	report, err := scanner.Scan(context.Background(), fs.Args())
+	if err != nil {
+		return c.errorf("%v", err)
+	}
+	if err := c.printReport(&opts, report); err != nil {
+		return c.errorf("%v", err)
+	}
+	if !report.OK() {
+		return exitError
+	}
+	return exitOK
+}
+
+func (c *cli) printReport(opts *options, report verify.Report) error {
+	if opts.format == "json" {
+		return writeJSON(c.stdout, report)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "XNAME\tSTATUS\tURL\tERROR")
+	for _, res := range report.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.Xname, res.Status, res.URL, res.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	statuses := make([]string, 0, len(report.Summary))
+	for status := range report.Summary {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+	counts := make([]string, len(statuses))
+	for i, status := range statuses {
+		counts[i] = fmt.Sprintf("%d %s", report.Summary[verify.Status(status)], status)
+	}
+	fmt.Fprintf(c.stdout, "\n%d checked: %s\n", len(report.Results), strings.Join(counts, ", "))
+	return nil
+}
@@ -1,6 +1,6 @@
 // MIT License
 //
-// (C) Copyright [2019, 2021] Hewlett Packard Enterprise Development LP
+// (C) Copyright [2019, 2021, 2026] Hewlett Packard Enterprise Development LP
 //
 // Permission is hereby granted, free of charge, to any person obtaining a
 // copy of this software and associated documentation files (the "Software"),
@@ -26,13 +26,19 @@ package compcredentials
 
 import (
 	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
 
-	log "github.com/sirupsen/logrus"
 	sstorage "github.com/Cray-HPE/hms-securestorage"
+	log "github.com/sirupsen/logrus"
 )
 
 const DefaultCompCredPath = "hms-creds"
 
+// The value that replaces secrets in redacted output.
+const RedactedValue = "<REDACTED>"
+
 // Usage example using vault as the backing secure storage:
 //import (
 //    "log"
@@ -98,6 +104,27 @@ const DefaultCompCredPath = "hms-creds"
 type CompCredStore struct {
 	CCPath string
 	SS     sstorage.SecureStorage
+
+	// Older key paths read, in order, for credentials not stored under
+	// CCPath, such as during a layout change.
+	FallbackPaths []string
+
+	// Store credentials found under a fallback path under CCPath as they
+	// are read, so they migrate as they are used.
+	MigrateOnRead bool
+
+	// The number of past versions of each component's credentials kept in
+	// the "<CCPath>-history" key space, when SS is not a VersionedStorage
+	// that keeps them itself. Zero keeps none.
+	HistoryLimit int
+
+	// Refuse passwords recently used for the same account.
+	PasswordReuse *ReusePolicy
+
+	// Record when and by whom credentials change as they are stored.
+	Metadata *MetadataPolicy
+
+	fallback fallbackCounters
 }
 
 // Create a new CompCredStore struct that uses a SecureStorage backing store.
@@ -117,6 +144,9 @@ func (ccs *CompCredStore) GetCompCred(xname string) (CompCredentials, error) {
 	if err != nil {
 		return compCred, err
 	}
+	if compCred.Xname == "" && len(ccs.FallbackPaths) > 0 {
+		return ccs.lookupFallback(xname)
+	}
 
 	return compCred, nil
 }
@@ -125,7 +155,13 @@ func (ccs *CompCredStore) GetCompCred(xname string) (CompCredentials, error) {
 func (ccs *CompCredStore) GetAllCompCreds() (map[string]CompCredentials, error) {
 	var compCreds map[string]CompCredentials
 
-	keyList, err := ccs.SS.LookupKeys(ccs.CCPath)
+	var keyList []string
+	var err error
+	if len(ccs.FallbackPaths) > 0 {
+		keyList, err = ccs.fallbackKeys()
+	} else {
+		keyList, err = LookupKeys(ccs.SS, ccs.CCPath)
+	}
 	if err != nil {
 		return compCreds, err
 	}
@@ -157,12 +193,102 @@ func (ccs *CompCredStore) GetCompCreds(xnames []string) (map[string]CompCredenti
 	return compCreds, nil
 }
 
-// Store the credentials for a component in the secure store.
+// Store the credentials for a component in the secure store. With a
+// PasswordReuse policy, passwords recently used for the same account are
+// refused with an error wrapping ErrPasswordReused.
 func (ccs *CompCredStore) StoreCompCred(compCred CompCredentials) error {
+	return ccs.storeCompCred(compCred, "", storeChanged)
+}
+
+// Store the credentials for a component as StoreCompCred does, recording
+// updater as who changed them if the store has a Metadata policy.
+func (ccs *CompCredStore) StoreCompCredAs(compCred CompCredentials, updater string) error {
+	return ccs.storeCompCred(compCred, updater, storeChanged)
+}
+
+// How storeCompCred treats the credentials it stores.
+type storeMode int
+
+const (
+	// Credentials being changed, which are checked for password reuse
+	// and stamped with metadata.
+	storeChanged storeMode = iota
+
+	// Earlier credentials made current again, which are not checked for
+	// password reuse but are stamped as a change.
+	storeRolledBack
+
+	// Credentials restored from a backup, which are not checked for
+	// password reuse and keep the metadata they carry, so that restoring
+	// them does not reset their age.
+	storeRestored
+
+	// Credentials copied from another key space or store, which are
+	// checked for password reuse but keep the metadata they carry, so
+	// that copying them does not reset their age.
+	storeCopied
+)
+
+// Store credentials, handling password reuse and metadata according to
+// mode.
+func (ccs *CompCredStore) storeCompCred(compCred CompCredentials, updater string, mode storeMode) error {
+	var (
+		reuseKey    string
+		reuseRecord *reuseRecord
+	)
+	if mode != storeRolledBack && mode != storeRestored {
+		var err error
+		if reuseKey, reuseRecord, err = ccs.reuseUpdate(compCred); err != nil {
+			return err
+		}
+	}
+	if err := ccs.stampMetadata(&compCred, updater, mode == storeRestored || mode == storeCopied); err != nil {
+		return err
+	}
+	var index historyIndex
+	keepHistory := ccs.emulateHistory()
+	if keepHistory {
+		var err error
+		if index, err = ccs.recordHistory(compCred.Xname); err != nil {
+			return err
+		}
+	}
+
 	err := ccs.SS.Store(ccs.CCPath+"/"+compCred.Xname, compCred)
 	if err != nil {
 		return err
 	}
+	if keepHistory {
+		if err := ccs.SS.Store(ccs.historyKey(compCred.Xname, "index"), index); err != nil {
+			return err
+		}
+	}
+	if reuseRecord != nil {
+		if err := ccs.SS.Store(reuseKey, *reuseRecord); err != nil {
+			return fmt.Errorf("stored, but unable to remember the passwords for %s: %v", compCred.Xname, err)
+		}
+	}
+
+	return nil
+}
+
+// Remove the credentials for a component from the secure store, including
+// any under a fallback path and their history. Removing credentials that do
+// not exist is not an error. The fingerprints kept by a PasswordReuse policy
+// are kept, so credentials cannot be deleted to reuse a password.
+func (ccs *CompCredStore) DeleteCompCred(xname string) error {
+	err := ccs.SS.Delete(ccs.CCPath + "/" + xname)
+	if err != nil {
+		return err
+	}
+	for _, path := range ccs.FallbackPaths {
+		if err := ccs.SS.Delete(path + "/" + xname); err != nil {
+			return err
+		}
+	}
+	if ccs.emulateHistory() {
+		return ccs.deleteHistory(xname)
+	}
 
 	return nil
 }
@@ -174,11 +300,94 @@ type CompCredentials struct {
 	Password     string `json:"password"`
 	SNMPAuthPass string `json:"SNMPAuthPass,omitempty"`
 	SNMPPrivPass string `json:"SNMPPrivPass,omitempty"`
+
+	// A PEM private key for SSH logins, and the host's pinned SSH public
+	// key in authorized_keys format.
+	SSHKey     string `json:"SSHKey,omitempty"`
+	SSHHostKey string `json:"SSHHostKey,omitempty"`
+
+	// When the credentials were first stored and last changed, as RFC 3339
+	// times, and who changed them. Set by StoreCompCred when the store has
+	// a Metadata policy.
+	Created   string `json:"created,omitempty"`
+	Updated   string `json:"updated,omitempty"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+
+	// An optional RFC 3339 time by which the credentials are due to be
+	// rotated.
+	Expires string `json:"expires,omitempty"`
+}
+
+// The CompCredentials fields describing the credentials rather than
+// holding them.
+var metadataFields = map[string]bool{
+	"Created":   true,
+	"Updated":   true,
+	"UpdatedBy": true,
+	"Expires":   true,
 }
 
 // Due to the sensitive nature of the data in CompCredentials, make a custom String function
 // to prevent passwords from being printed directly (accidentally) to output.
 func (compCred CompCredentials) String() string {
-	return fmt.Sprintf("URL: %s, Username: %s, Password: <REDACTED>, SNMP Passes: <REDACTED>/<REDACTED>",
+	return fmt.Sprintf("URL: %s, Username: %s, Password: <REDACTED>, SNMP Passes: <REDACTED>/<REDACTED>, SSH Key: <REDACTED>",
 		compCred.URL, compCred.Username)
 }
+
+// Get a copy of the credentials with every non-empty secret replaced by
+// RedactedValue, for display.
+func (compCred CompCredentials) Redacted() CompCredentials {
+	redact := func(s string) string {
+		if s == "" {
+			return s
+		}
+		return RedactedValue
+	}
+	compCred.Password = redact(compCred.Password)
+	compCred.SNMPAuthPass = redact(compCred.SNMPAuthPass)
+	compCred.SNMPPrivPass = redact(compCred.SNMPPrivPass)
+	compCred.SSHKey = redact(compCred.SSHKey)
+	return compCred
+}
+
+// Get the JSON names of the fields that differ between two sets of
+// credentials. Only the names are returned so that callers can report a
+// difference without revealing either value. Metadata such as the Updated
+// time is not compared.
+func ChangedFields(a, b CompCredentials) []string {
+	var fields []string
+
+	av := reflect.ValueOf(a)
+	bv := reflect.ValueOf(b)
+	for i := 0; i < av.NumField(); i++ {
+		if metadataFields[av.Type().Field(i).Name] {
+			continue
+		}
+		if reflect.DeepEqual(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(av.Type().Field(i).Tag.Get("json"), ",")
+		fields = append(fields, name)
+	}
+
+	return fields
+}
+
+// List the keys under a path, treating a path with no keys as empty. The
+// Vault adapter dereferences a nil secret listing such a path, as Vault
+// returns no data for it; that panic is recovered. Any other panic, and any
+// from another SecureStorage, is not. The KV2Adapter recovers it itself.
+func LookupKeys(ss sstorage.SecureStorage, path string) (keys []string, err error) {
+	if _, ok := ss.(*sstorage.VaultAdapter); !ok {
+		return ss.LookupKeys(path)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if rerr, ok := r.(runtime.Error); !ok || !strings.Contains(rerr.Error(), "nil pointer dereference") {
+				panic(r)
+			}
+			keys, err = nil, nil
+		}
+	}()
+	return ss.LookupKeys(path)
+}
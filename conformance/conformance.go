@@ -0,0 +1,326 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package conformance defines the behaviour CompCredStore expects from a
+// SecureStorage implementation and provides a test suite that checks it.
+//
+// The expected semantics are:
+//
+//   - Store replaces the whole value at a key. Fields left out of the new
+//     value do not survive from the old one.
+//   - Lookup of a key that does not exist returns no error and leaves the
+//     output untouched.
+//   - LookupKeys returns the immediate children of a path, relative to that
+//     path. Children that are themselves paths carry a trailing "/". The
+//     order is not significant.
+//   - LookupKeys of a path with nothing under it returns either an empty
+//     list or an error. It must not panic.
+//   - Delete removes a key. Deleting a key that does not exist is not an
+//     error.
+//   - Key spaces are isolated: nothing stored under "a-b" or "b" appears
+//     under "a".
+//
+// Usage from a backend's own tests:
+//
+//	func TestConformance(t *testing.T) {
+//	    conformance.RunSuite(t, func(t *testing.T) sstorage.SecureStorage {
+//	        return newMyStorage(t)
+//	    })
+//	}
+package conformance
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Factory returns a fresh, empty SecureStorage for a single test.
+type Factory func(t *testing.T) sstorage.SecureStorage
+
+// Suite is the conformance test suite for one SecureStorage implementation.
+type Suite struct {
+	// New returns the storage under test. It is called once per test.
+	New Factory
+
+	// Skip maps test names to the reason they are skipped, for backends
+	// with known deviations.
+	Skip map[string]string
+}
+
+type suiteTest struct {
+	name string
+	fn   func(t *testing.T, ss sstorage.SecureStorage)
+}
+
+var suiteTests = []suiteTest{
+	{"StoreLookup", testStoreLookup},
+	{"StoreOverwrite", testStoreOverwrite},
+	{"LookupMissingKey", testLookupMissingKey},
+	{"LookupKeysChildren", testLookupKeysChildren},
+	{"LookupKeysNested", testLookupKeysNested},
+	{"LookupKeysMissingPath", testLookupKeysMissingPath},
+	{"Delete", testDelete},
+	{"DeleteMissingKey", testDeleteMissingKey},
+	{"KeySpaceIsolation", testKeySpaceIsolation},
+	{"CompCredStore", testCompCredStore},
+	{"CompCredStoreMissing", testCompCredStoreMissing},
+}
+
+// Get the names of every test in the suite, for use with Suite.Skip.
+func TestNames() []string {
+	names := make([]string, 0, len(suiteTests))
+	for _, st := range suiteTests {
+		names = append(names, st.name)
+	}
+	return names
+}
+
+// Run the conformance suite with one subtest per expected behaviour.
+func RunSuite(t *testing.T, newSS Factory) {
+	Suite{New: newSS}.Run(t)
+}
+
+// Run the conformance suite with one subtest per expected behaviour.
+func (s Suite) Run(t *testing.T) {
+	for _, st := range suiteTests {
+		st := st
+		t.Run(st.name, func(t *testing.T) {
+			if reason, ok := s.Skip[st.name]; ok {
+				t.Skip(reason)
+			}
+			st.fn(t, s.New(t))
+		})
+	}
+}
+
+const basePath = "conformance/hms-creds"
+
+var testCreds = []cc.CompCredentials{
+	{
+		Xname:        "x0c0s1b0",
+		URL:          "10.4.0.21/redfish/v1/UpdateService",
+		Username:     "test1",
+		Password:     "123",
+		SNMPAuthPass: "auth1",
+		SNMPPrivPass: "priv1",
+	}, {
+		Xname:    "x0c0s2b0",
+		URL:      "10.4.0.22/redfish/v1/UpdateService",
+		Username: "test2",
+		Password: "456",
+	},
+}
+
+func mustStore(t *testing.T, ss sstorage.SecureStorage, key string, value interface{}) {
+	t.Helper()
+	if err := ss.Store(key, value); err != nil {
+		t.Fatalf("Store(%q) failed: %v", key, err)
+	}
+}
+
+func lookupKeys(t *testing.T, ss sstorage.SecureStorage, keyPath string) []string {
+	t.Helper()
+	klist, err := ss.LookupKeys(keyPath)
+	if err != nil {
+		t.Fatalf("LookupKeys(%q) failed: %v", keyPath, err)
+	}
+	sorted := append([]string{}, klist...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func testStoreLookup(t *testing.T, ss sstorage.SecureStorage) {
+	for _, cred := range testCreds {
+		mustStore(t, ss, basePath+"/"+cred.Xname, cred)
+	}
+	for _, cred := range testCreds {
+		var out cc.CompCredentials
+		if err := ss.Lookup(basePath+"/"+cred.Xname, &out); err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", cred.Xname, err)
+		}
+		if !reflect.DeepEqual(out, cred) {
+			t.Errorf("Expected %s to read back as stored, but fields differ", cred.Xname)
+		}
+	}
+}
+
+func testStoreOverwrite(t *testing.T, ss sstorage.SecureStorage) {
+	key := basePath + "/" + testCreds[0].Xname
+	mustStore(t, ss, key, testCreds[0])
+
+	replacement := cc.CompCredentials{
+		Xname:    testCreds[0].Xname,
+		URL:      testCreds[0].URL,
+		Username: "root",
+		Password: "789",
+	}
+	mustStore(t, ss, key, replacement)
+
+	var out cc.CompCredentials
+	if err := ss.Lookup(key, &out); err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if !reflect.DeepEqual(out, replacement) {
+		t.Errorf("Expected Store to replace the whole value; SNMP fields or credentials were retained or lost")
+	}
+}
+
+func testLookupMissingKey(t *testing.T, ss sstorage.SecureStorage) {
+	out := cc.CompCredentials{Xname: "untouched"}
+	if err := ss.Lookup(basePath+"/x9999c0s0b0", &out); err != nil {
+		t.Fatalf("Expected no error for a missing key but got: %v", err)
+	}
+	if out.Xname != "untouched" || out.Password != "" {
+		t.Errorf("Expected output to be left untouched for a missing key")
+	}
+}
+
+func testLookupKeysChildren(t *testing.T, ss sstorage.SecureStorage) {
+	for _, cred := range testCreds {
+		mustStore(t, ss, basePath+"/"+cred.Xname, cred)
+	}
+	klist := lookupKeys(t, ss, basePath)
+	expected := []string{"x0c0s1b0", "x0c0s2b0"}
+	if !reflect.DeepEqual(klist, expected) {
+		t.Errorf("Expected keys %v but got %v", expected, klist)
+	}
+}
+
+func testLookupKeysNested(t *testing.T, ss sstorage.SecureStorage) {
+	mustStore(t, ss, basePath+"/x0c0s1b0", testCreds[0])
+	mustStore(t, ss, basePath+"/sub/x0c0s2b0", testCreds[1])
+	klist := lookupKeys(t, ss, basePath)
+	expected := []string{"sub/", "x0c0s1b0"}
+	if !reflect.DeepEqual(klist, expected) {
+		t.Errorf("Expected keys %v but got %v", expected, klist)
+	}
+	klist = lookupKeys(t, ss, basePath+"/sub")
+	expected = []string{"x0c0s2b0"}
+	if !reflect.DeepEqual(klist, expected) {
+		t.Errorf("Expected keys %v but got %v", expected, klist)
+	}
+}
+
+func testLookupKeysMissingPath(t *testing.T, ss sstorage.SecureStorage) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("LookupKeys of an empty path panicked: %v", r)
+		}
+	}()
+	klist, err := ss.LookupKeys(basePath + "-empty")
+	if err == nil && len(klist) != 0 {
+		t.Errorf("Expected no keys for an empty path but got %v", klist)
+	}
+}
+
+func testDelete(t *testing.T, ss sstorage.SecureStorage) {
+	for _, cred := range testCreds {
+		mustStore(t, ss, basePath+"/"+cred.Xname, cred)
+	}
+	if err := ss.Delete(basePath + "/" + testCreds[0].Xname); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	var out cc.CompCredentials
+	if err := ss.Lookup(basePath+"/"+testCreds[0].Xname, &out); err != nil {
+		t.Fatalf("Lookup after Delete failed: %v", err)
+	}
+	if !reflect.DeepEqual(out, cc.CompCredentials{}) {
+		t.Errorf("Expected nothing at a deleted key")
+	}
+	klist := lookupKeys(t, ss, basePath)
+	expected := []string{testCreds[1].Xname}
+	if !reflect.DeepEqual(klist, expected) {
+		t.Errorf("Expected keys %v after Delete but got %v", expected, klist)
+	}
+}
+
+func testDeleteMissingKey(t *testing.T, ss sstorage.SecureStorage) {
+	if err := ss.Delete(basePath + "/x9999c0s0b0"); err != nil {
+		t.Errorf("Expected no error deleting a missing key but got: %v", err)
+	}
+}
+
+func testKeySpaceIsolation(t *testing.T, ss sstorage.SecureStorage) {
+	mustStore(t, ss, basePath+"/"+testCreds[0].Xname, testCreds[0])
+	mustStore(t, ss, basePath+"-other/"+testCreds[1].Xname, testCreds[1])
+	mustStore(t, ss, "conformance/other/"+testCreds[1].Xname, testCreds[1])
+
+	klist := lookupKeys(t, ss, basePath)
+	expected := []string{testCreds[0].Xname}
+	if !reflect.DeepEqual(klist, expected) {
+		t.Errorf("Expected keys %v but got %v", expected, klist)
+	}
+}
+
+func testCompCredStore(t *testing.T, ss sstorage.SecureStorage) {
+	ccs := cc.NewCompCredStore(basePath, ss)
+	for _, cred := range testCreds {
+		if err := ccs.StoreCompCred(cred); err != nil {
+			t.Fatalf("StoreCompCred(%s) failed: %v", cred.Xname, err)
+		}
+	}
+
+	cred, err := ccs.GetCompCred(testCreds[0].Xname)
+	if err != nil {
+		t.Fatalf("GetCompCred failed: %v", err)
+	}
+	if !reflect.DeepEqual(cred, testCreds[0]) {
+		t.Errorf("Expected GetCompCred to return the stored credentials")
+	}
+
+	expected := map[string]cc.CompCredentials{
+		testCreds[0].Xname: testCreds[0],
+		testCreds[1].Xname: testCreds[1],
+	}
+	creds, err := ccs.GetCompCreds([]string{testCreds[0].Xname, testCreds[1].Xname})
+	if err != nil {
+		t.Fatalf("GetCompCreds failed: %v", err)
+	}
+	if !reflect.DeepEqual(creds, expected) {
+		t.Errorf("Expected GetCompCreds to return %v but got %v", expected, creds)
+	}
+
+	creds, err = ccs.GetAllCompCreds()
+	if err != nil {
+		t.Fatalf("GetAllCompCreds failed: %v", err)
+	}
+	if !reflect.DeepEqual(creds, expected) {
+		t.Errorf("Expected GetAllCompCreds to return %v but got %v", expected, creds)
+	}
+}
+
+func testCompCredStoreMissing(t *testing.T, ss sstorage.SecureStorage) {
+	ccs := cc.NewCompCredStore(basePath, ss)
+	cred, err := ccs.GetCompCred("x9999c0s0b0")
+	if err != nil {
+		t.Fatalf("Expected no error for missing credentials but got: %v", err)
+	}
+	if !reflect.DeepEqual(cred, cc.CompCredentials{}) {
+		t.Errorf("Expected empty credentials for a missing xname")
+	}
+}
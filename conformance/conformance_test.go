@@ -0,0 +1,53 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package conformance
+
+import (
+	"testing"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	RunSuite(t, func(t *testing.T) sstorage.SecureStorage {
+		return NewMemoryStorage()
+	})
+}
+
+func TestSuiteSkip(t *testing.T) {
+	skip := make(map[string]string)
+	for _, name := range TestNames() {
+		skip[name] = "skipped by TestSuiteSkip"
+	}
+	calls := 0
+	Suite{
+		New: func(t *testing.T) sstorage.SecureStorage {
+			calls++
+			return NewMemoryStorage()
+		},
+		Skip: skip,
+	}.Run(t)
+	if calls != 0 {
+		t.Errorf("Expected no storage to be created for skipped tests but got %v", calls)
+	}
+}
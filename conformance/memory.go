@@ -0,0 +1,148 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// MemoryStorage is an in-memory SecureStorage that follows the semantics
+// documented by the conformance suite. Values are converted the same way the
+// Vault adapter converts them (mapstructure to a map, JSON over the wire,
+// mapstructure back into the caller's struct), so anything that round trips
+// through MemoryStorage will also round trip through Vault.
+type MemoryStorage struct {
+	mu   sync.Mutex
+	data map[string]map[string]interface{}
+}
+
+// Create a new, empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		data: make(map[string]map[string]interface{}),
+	}
+}
+
+func cleanKey(key string) string {
+	return strings.Trim(key, "/")
+}
+
+// Convert a value into the map form a Vault secret would hold.
+func toSecretData(value interface{}) (map[string]interface{}, error) {
+	var data map[string]interface{}
+
+	err := mapstructure.Decode(value, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round trip through JSON so the stored types match what Vault hands back.
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	data = nil
+	err = json.Unmarshal(buf, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Store a value at key, replacing anything previously stored there.
+func (ms *MemoryStorage) Store(key string, value interface{}) error {
+	data, err := toSecretData(value)
+	if err != nil {
+		return err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.data[cleanKey(key)] = data
+	return nil
+}
+
+// Store a value at key. There is no response body for an in-memory write, so
+// output is left untouched.
+func (ms *MemoryStorage) StoreWithData(key string, value interface{}, output interface{}) error {
+	return ms.Store(key, value)
+}
+
+// Read the value at key into output. A missing key is not an error; output is
+// left untouched.
+func (ms *MemoryStorage) Lookup(key string, output interface{}) error {
+	if output == nil {
+		return fmt.Errorf("output interface was nil")
+	}
+
+	ms.mu.Lock()
+	data, ok := ms.data[cleanKey(key)]
+	ms.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return mapstructure.Decode(data, output)
+}
+
+// Remove the value at key. Removing a missing key is not an error.
+func (ms *MemoryStorage) Delete(key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.data, cleanKey(key))
+	return nil
+}
+
+// List the immediate children of keyPath. Leaf keys are returned by name and
+// intermediate paths are returned with a trailing "/", as Vault does.
+func (ms *MemoryStorage) LookupKeys(keyPath string) ([]string, error) {
+	prefix := cleanKey(keyPath) + "/"
+	seen := make(map[string]bool)
+	klist := []string{}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for key := range ms.data {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		child := strings.TrimPrefix(key, prefix)
+		if i := strings.Index(child, "/"); i >= 0 {
+			child = child[:i+1]
+		}
+		if !seen[child] {
+			seen[child] = true
+			klist = append(klist, child)
+		}
+	}
+	sort.Strings(klist)
+
+	return klist, nil
+}
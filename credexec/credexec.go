@@ -0,0 +1,181 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package credexec runs a command with a component's credentials in its
+// environment. The credentials are only given to the child process: they are
+// never placed on its command line, and the copies this process makes to
+// build the child's environment are zeroed once the child has started.
+//
+// Only those copies are scrubbed. The plaintext password and other fields of
+// the CompCredentials value read from the store are Go strings, which cannot
+// be zeroed, and stay in this process's memory until they are garbage
+// collected and the memory is reused.
+package credexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// EnvNames are the environment variables that receive each field. An empty
+// name leaves that field out of the child's environment.
+type EnvNames struct {
+	Username string
+	Password string
+	URL      string
+}
+
+// The variables used when Options.Env is not set.
+var DefaultEnvNames = EnvNames{
+	Username: "COMPCRED_USERNAME",
+	Password: "COMPCRED_PASSWORD",
+	URL:      "COMPCRED_URL",
+}
+
+// Options control how the command is run.
+type Options struct {
+	// The variables to set. Defaults to DefaultEnvNames; names left empty
+	// here are omitted.
+	Env *EnvNames
+
+	// The rest of the child's environment. Defaults to os.Environ(). Any
+	// entries for the names in Env are dropped.
+	BaseEnv []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Look up the credentials for xname and run argv with them in its
+// environment, waiting for it to finish. The child's exit code is returned;
+// a child killed by a signal is reported as 128 plus the signal number, as
+// shells do. An error is returned if the command could not be run at all.
+func Run(ctx context.Context, ccs *cc.CompCredStore, xname string, argv []string, opts Options) (int, error) {
+	if len(argv) == 0 {
+		return -1, fmt.Errorf("no command given")
+	}
+
+	cred, err := ccs.GetCompCred(xname)
+	if err != nil {
+		return -1, fmt.Errorf("unable to get credentials for %s: %v", xname, err)
+	}
+	if cred.Xname == "" {
+		return -1, fmt.Errorf("no credentials stored for %s", xname)
+	}
+
+	names := DefaultEnvNames
+	if opts.Env != nil {
+		names = *opts.Env
+	}
+	base := opts.BaseEnv
+	if base == nil {
+		base = os.Environ()
+	}
+
+	env, scrub := buildEnv(base, names, cred)
+	defer scrub()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = env
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	err = cmd.Start()
+	// The child has its own copy of the environment now.
+	cmd.Env = nil
+	scrub()
+	if err != nil {
+		return -1, err
+	}
+
+	err = cmd.Wait()
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return 128 + int(ws.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	return -1, err
+}
+
+// Build the child's environment. The secret entries are backed by buffers
+// owned here; the returned function zeroes them, after which the strings in
+// the environment must not be used.
+func buildEnv(base []string, names EnvNames, cred cc.CompCredentials) ([]string, func()) {
+	vars := []struct {
+		name  string
+		value string
+	}{
+		{names.Username, cred.Username},
+		{names.Password, cred.Password},
+		{names.URL, cred.URL},
+	}
+
+	drop := make(map[string]bool)
+	for _, v := range vars {
+		if v.name != "" {
+			drop[v.name] = true
+		}
+	}
+
+	env := make([]string, 0, len(base)+len(vars))
+	for _, kv := range base {
+		name, _, _ := strings.Cut(kv, "=")
+		if !drop[name] {
+			env = append(env, kv)
+		}
+	}
+
+	var bufs [][]byte
+	for _, v := range vars {
+		if v.name == "" {
+			continue
+		}
+		buf := make([]byte, 0, len(v.name)+1+len(v.value))
+		buf = append(buf, v.name...)
+		buf = append(buf, '=')
+		buf = append(buf, v.value...)
+		bufs = append(bufs, buf)
+		env = append(env, unsafe.String(unsafe.SliceData(buf), len(buf)))
+	}
+
+	return env, func() {
+		for _, buf := range bufs {
+			clear(buf)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package credexec
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+const printEnv = `printf '%s|%s|%s|%s' "$COMPCRED_USERNAME" "$COMPCRED_PASSWORD" "$COMPCRED_URL" "$IPMI_PASSWORD"`
+
+func TestRun(t *testing.T) {
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	ccs.StoreCompCred(cc.CompCredentials{
+		Xname:    "x1000c0s1b0",
+		URL:      "10.1.1.1/redfish/v1",
+		Username: "root",
+		Password: "s3cret",
+	})
+
+	var tests = []struct {
+		xname   string
+		argv    []string
+		opts    Options
+		code    int
+		stdout  string
+		respErr bool
+	}{
+		{
+			xname:  "x1000c0s1b0",
+			argv:   []string{"sh", "-c", printEnv},
+			opts:   Options{BaseEnv: []string{"COMPCRED_PASSWORD=stale", "IPMI_PASSWORD=other"}},
+			stdout: "root|s3cret|10.1.1.1/redfish/v1|other",
+		}, {
+			xname:  "x1000c0s1b0",
+			argv:   []string{"sh", "-c", printEnv},
+			opts:   Options{Env: &EnvNames{Password: "IPMI_PASSWORD"}, BaseEnv: []string{}},
+			stdout: "|||s3cret",
+		}, {
+			xname:  "x1000c0s1b0",
+			argv:   []string{"sh", "-c", printEnv},
+			opts:   Options{Env: &EnvNames{}, BaseEnv: []string{}},
+			stdout: "|||",
+		}, {
+			xname: "x1000c0s1b0",
+			argv:  []string{"sh", "-c", "exit 3"},
+			code:  3,
+		}, {
+			xname: "x1000c0s1b0",
+			argv:  []string{"sh", "-c", "kill -TERM $$"},
+			code:  128 + 15,
+		}, {
+			xname:   "x9999c0s0b0",
+			argv:    []string{"true"},
+			respErr: true,
+		}, {
+			xname:   "x1000c0s1b0",
+			argv:    nil,
+			respErr: true,
+		}, {
+			xname:   "x1000c0s1b0",
+			argv:    []string{"/nonexistent/command"},
+			respErr: true,
+		},
+	}
+
+	for i, test := range tests {
+		var stdout bytes.Buffer
+		test.opts.Stdout = &stdout
+		code, err := Run(context.Background(), ccs, test.xname, test.argv, test.opts)
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if code != test.code {
+			t.Errorf("Test %v Failed: Expected exit code %v but got %v", i, test.code, code)
+		}
+		if stdout.String() != test.stdout {
+			t.Errorf("Test %v Failed: Expected output %q but got %q", i, test.stdout, stdout.String())
+		}
+	}
+}
+
+func TestBuildEnvScrub(t *testing.T) {
+	cred := cc.CompCredentials{Username: "root", Password: "s3cret", URL: "10.1.1.1"}
+	env, scrub := buildEnv([]string{"PATH=/bin"}, DefaultEnvNames, cred)
+
+	joined := strings.Join(env, "\n")
+	if !strings.Contains(joined, "COMPCRED_PASSWORD=s3cret") || !strings.Contains(joined, "PATH=/bin") {
+		t.Fatalf("Unexpected environment %q", joined)
+	}
+
+	scrub()
+	for _, kv := range env[1:] {
+		if strings.Trim(kv, "\x00") != "" {
+			t.Errorf("Expected secret entries to be zeroed but got %q", kv)
+		}
+	}
+	if env[0] != "PATH=/bin" {
+		t.Errorf("Expected base entries to be left alone but got %q", env[0])
+	}
+}
@@ -0,0 +1,212 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package desired keeps credentials in a declared state. A spec, in YAML or
+// JSON and safe to keep in Git, lists the components, their usernames and
+// URLs, and where each secret comes from: generated, or read from an
+// environment variable or file. Plan compares a spec with a CompCredStore
+// and Apply makes the store match it.
+package desired
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// The spec version this package reads.
+const SpecVersion = 1
+
+// Secret says where a secret comes from. Secrets are never written in a
+// spec itself.
+type Secret struct {
+	// Generate a password meeting Policy if none is stored. A stored one
+	// is kept. SSH keys cannot be generated.
+	Generate bool               `json:"generate,omitempty"`
+	Policy   *cc.PasswordPolicy `json:"policy,omitempty"`
+
+	// Read the secret from "env:NAME" or "file:PATH". Relative paths are
+	// relative to the spec file.
+	Ref string `json:"ref,omitempty"`
+}
+
+// Component is the desired state of one component's credentials. Fields
+// left out are not managed, and whatever is stored for them is kept.
+type Component struct {
+	Xname        string  `json:"xname"`
+	URL          string  `json:"url,omitempty"`
+	Username     string  `json:"username,omitempty"`
+	Password     *Secret `json:"password,omitempty"`
+	SNMPAuthPass *Secret `json:"snmpAuthPass,omitempty"`
+	SNMPPrivPass *Secret `json:"snmpPrivPass,omitempty"`
+	SSHKey       *Secret `json:"sshKey,omitempty"`
+
+	// Not a secret, so given as is.
+	SSHHostKey string `json:"sshHostKey,omitempty"`
+}
+
+// Spec is a desired state file:
+//
+//	version: 1
+//	prune: false
+//	defaults:
+//	  username: root
+//	  password: {generate: true, policy: {min_length: 16}}
+//	components:
+//	- xname: x1000c0s0b0
+//	  url: x1000c0s0b0
+//	- xname: x3000c0w14
+//	  username: admin
+//	  password: {ref: "env:SWITCH_PASSWORD"}
+//	  snmpAuthPass: {ref: "file:secrets/snmp-auth"}
+type Spec struct {
+	Version int `json:"version"`
+
+	// Delete stored credentials for components the spec does not list.
+	Prune bool `json:"prune,omitempty"`
+
+	// Settings for every component that does not give its own.
+	Defaults Component `json:"defaults"`
+
+	Components []Component `json:"components"`
+
+	// The directory relative secret file references are read from.
+	dir string
+}
+
+// Parse a spec in YAML or JSON. Unknown fields are errors, so typos are not
+// silently ignored.
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Version != SpecVersion {
+		return nil, fmt.Errorf("unsupported spec version %d", spec.Version)
+	}
+	if spec.Defaults.Xname != "" {
+		return nil, fmt.Errorf("defaults cannot have an xname")
+	}
+	if spec.Defaults.SSHKey != nil && spec.Defaults.SSHKey.Generate {
+		return nil, fmt.Errorf("defaults: SSHKey cannot be generated and must have a ref")
+	}
+
+	seen := make(map[string]bool, len(spec.Components))
+	for i := range spec.Components {
+		comp := &spec.Components[i]
+		if comp.Xname == "" {
+			return nil, fmt.Errorf("component %d has no xname", i)
+		}
+		if seen[comp.Xname] {
+			return nil, fmt.Errorf("%s appears more than once", comp.Xname)
+		}
+		seen[comp.Xname] = true
+		comp.applyDefaults(spec.Defaults)
+		for name, s := range comp.secrets() {
+			if *s == nil {
+				continue
+			}
+			if (*s).Generate == ((*s).Ref != "") {
+				return nil, fmt.Errorf("%s: %s must either be generated or have a ref", comp.Xname, name)
+			}
+			// Only passwords are generated, not key pairs.
+			if (*s).Generate && name == "SSHKey" {
+				return nil, fmt.Errorf("%s: SSHKey cannot be generated and must have a ref", comp.Xname)
+			}
+		}
+	}
+	return &spec, nil
+}
+
+// Read a spec from a file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	spec.dir = filepath.Dir(path)
+	return spec, nil
+}
+
+func (comp *Component) applyDefaults(d Component) {
+	if comp.URL == "" {
+		comp.URL = d.URL
+	}
+	if comp.Username == "" {
+		comp.Username = d.Username
+	}
+	if comp.SSHHostKey == "" {
+		comp.SSHHostKey = d.SSHHostKey
+	}
+	defaults := d.secrets()
+	for name, s := range comp.secrets() {
+		if *s == nil {
+			*s = *defaults[name]
+		}
+	}
+}
+
+// Get the component's secrets by the JSON name of the field they fill.
+func (comp *Component) secrets() map[string]**Secret {
+	return map[string]**Secret{
+		"password":     &comp.Password,
+		"SNMPAuthPass": &comp.SNMPAuthPass,
+		"SNMPPrivPass": &comp.SNMPPrivPass,
+		"SSHKey":       &comp.SSHKey,
+	}
+}
+
+// Read a secret reference.
+func (spec *Spec) resolve(ref string) (string, error) {
+	kind, name, _ := strings.Cut(ref, ":")
+	switch kind {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	case "file":
+		if !filepath.IsAbs(name) && spec.dir != "" {
+			name = filepath.Join(spec.dir, name)
+		}
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		value := strings.TrimSuffix(strings.TrimSuffix(string(buf), "\n"), "\r")
+		if value == "" {
+			return "", fmt.Errorf("%s is empty", name)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown secret reference %q; use env:NAME or file:PATH", ref)
+}
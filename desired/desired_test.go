@@ -0,0 +1,190 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package desired
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+	"github.com/Cray-HPE/hms-compcredentials/vaulttest"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		spec string
+		err  string
+	}{
+		{"version: 1\ncomponents:\n- xname: x1000c0s0b0\n  username: root\n", ""},
+		{`{"version": 1, "components": [{"xname": "x1000c0s0b0", "password": {"ref": "env:PW"}}]}`, ""},
+		{"version: 2\ncomponents: []\n", "unsupported spec version"},
+		{"version: 1\ncomponents:\n- xname: x1000c0s0b0\n  pasword: {generate: true}\n", "unknown field"},
+		{"version: 1\ncomponents:\n- username: root\n", "no xname"},
+		{"version: 1\ncomponents:\n- xname: x1\n- xname: x1\n", "more than once"},
+		{"version: 1\ncomponents:\n- xname: x1\n  password: {generate: true, ref: 'env:PW'}\n", "either be generated or have a ref"},
+		{"version: 1\ncomponents:\n- xname: x1\n  password: {}\n", "either be generated or have a ref"},
+		{"version: 1\ndefaults: {xname: x1}\ncomponents: []\n", "defaults cannot have an xname"},
+		{"version: 1\ncomponents:\n- xname: x1\n  sshKey: {generate: true}\n", "SSHKey cannot be generated"},
+		{"version: 1\ndefaults: {sshKey: {generate: true}}\ncomponents: []\n", "SSHKey cannot be generated"},
+		{"version: 1\ncomponents:\n- xname: x1\n  sshKey: {ref: 'file:x1.key'}\n", ""},
+	}
+
+	for i, test := range tests {
+		_, err := Parse([]byte(test.spec))
+		if test.err == "" && err != nil {
+			t.Errorf("Test %v Failed: Unexpected error %v", i, err)
+		} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("Test %v Failed: Expected an error containing %q but got %v", i, test.err, err)
+		}
+	}
+}
+
+const testSpec = `version: 1
+prune: true
+defaults:
+  username: root
+  password:
+    generate: true
+    policy: {min_length: 20, max_length: 20, require_digit: true}
+components:
+- xname: x1000c0s0b0
+  url: x1000c0s0b0
+- xname: x1000c0s1b0
+  url: x1000c0s1b0
+- xname: x3000c0w14
+  username: admin
+  password: {ref: "env:TEST_SWITCH_PASSWORD"}
+  snmpAuthPass: {ref: "file:secrets/snmp-auth"}
+`
+
+func TestPlanApply(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "secrets"), 0700)
+	os.WriteFile(filepath.Join(dir, "secrets", "snmp-auth"), []byte("snmp-secret\n"), 0600)
+	os.WriteFile(filepath.Join(dir, "creds.yaml"), []byte(testSpec), 0600)
+	t.Setenv("TEST_SWITCH_PASSWORD", "switch-secret")
+
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	// Already has a password, which is kept, and an unmanaged SNMP one.
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", URL: "old", Username: "root", Password: "kept", SNMPAuthPass: "unmanaged"})
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x9000c0s0b0", Username: "root", Password: "pruned"})
+
+	spec, err := Load(filepath.Join(dir, "creds.yaml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	p, err := NewPlan(ccs, spec)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	expected := []Change{
+		{Xname: "x1000c0s0b0", Action: "create", Fields: []string{"url", "username"}, Generate: []string{"password"}},
+		{Xname: "x1000c0s1b0", Action: "update", Fields: []string{"url"}},
+		{Xname: "x3000c0w14", Action: "create", Fields: []string{"username", "password", "SNMPAuthPass"}},
+		{Xname: "x9000c0s0b0", Action: "delete"},
+	}
+	if !reflect.DeepEqual(p.Changes, expected) {
+		t.Errorf("Expected plan %+v but got %+v", expected, p.Changes)
+	}
+	if all, _ := ccs.GetAllCompCreds(); len(all) != 2 {
+		t.Errorf("Expected planning to change nothing but got %v", all)
+	}
+
+	if err := p.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	generated, _ := ccs.GetCompCred("x1000c0s0b0")
+	if len(generated.Password) != 20 || !strings.ContainsAny(generated.Password, "0123456789") {
+		t.Errorf("Expected a generated password meeting the policy but got %d characters", len(generated.Password))
+	}
+	if cred, _ := ccs.GetCompCred("x1000c0s1b0"); cred.Password != "kept" || cred.SNMPAuthPass != "unmanaged" || cred.URL != "x1000c0s1b0" {
+		t.Errorf("Expected the stored password and unmanaged fields to be kept but got %#v", cred)
+	}
+	sw, _ := ccs.GetCompCred("x3000c0w14")
+	if sw.Username != "admin" || sw.Password != "switch-secret" || sw.SNMPAuthPass != "snmp-secret" {
+		t.Errorf("Expected referenced secrets to be stored but got %#v", sw)
+	}
+	if cred, _ := ccs.GetCompCred("x9000c0s0b0"); cred.Xname != "" {
+		t.Errorf("Expected x9000c0s0b0 to be pruned")
+	}
+
+	// Once applied, there is nothing left to do, and generated passwords
+	// are not regenerated.
+	p, err = NewPlan(ccs, spec)
+	if err != nil || len(p.Changes) != 0 {
+		t.Errorf("Expected an empty plan but got %+v, %v", p.Changes, err)
+	}
+	if cred, _ := ccs.GetCompCred("x1000c0s0b0"); cred.Password != generated.Password {
+		t.Errorf("Expected the generated password to be kept")
+	}
+
+	// A rotated referenced secret is picked up.
+	t.Setenv("TEST_SWITCH_PASSWORD", "rotated")
+	p, _ = Apply(ccs, spec)
+	if len(p.Changes) != 1 || !reflect.DeepEqual(p.Changes[0].Fields, []string{"password"}) {
+		t.Errorf("Expected only the switch password to change but got %+v", p.Changes)
+	}
+
+	os.Unsetenv("TEST_SWITCH_PASSWORD")
+	if _, err := NewPlan(ccs, spec); err == nil || !strings.Contains(err.Error(), "TEST_SWITCH_PASSWORD") {
+		t.Errorf("Expected an error for an unset reference but got %v", err)
+	}
+}
+
+func TestApplyEmptyVault(t *testing.T) {
+	// Bootstrapping a new Vault must not hit the VaultAdapter's empty path
+	// panic.
+	vault := vaulttest.NewServer()
+	t.Cleanup(vault.Close)
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, vault.NewVaultAdapter(t, "secret"))
+	spec, err := Parse([]byte("version: 1\ncomponents:\n- xname: x1000c0s0b0\n  username: root\n  password: {generate: true}\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	p, err := NewPlan(ccs, spec)
+	expected := []Change{{Xname: "x1000c0s0b0", Action: "create", Fields: []string{"username"}, Generate: []string{"password"}}}
+	if err != nil || !reflect.DeepEqual(p.Changes, expected) {
+		t.Fatalf("Expected plan %+v but got %+v, %v", expected, p.Changes, err)
+	}
+	if err := p.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if cred, _ := ccs.GetCompCred("x1000c0s0b0"); cred.Username != "root" || cred.Password == "" {
+		t.Errorf("Expected the credentials to be stored in Vault but got %q", cred.Username)
+	}
+}
+
+func TestCredField(t *testing.T) {
+	var cred cc.CompCredentials
+	if field, err := credField(&cred, "SNMPPrivPass"); err != nil || field != &cred.SNMPPrivPass {
+		t.Errorf("Expected the SNMPPrivPass field but got %v, %v", field, err)
+	}
+	if _, err := credField(&cred, "bogus"); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,225 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package desired
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// The JSON names of the secret fields, in the order they are reported.
+var secretFields = []string{"password", "SNMPAuthPass", "SNMPPrivPass", "SSHKey"}
+
+// Change is what a Plan does to one component's credentials.
+type Change struct {
+	Xname string `json:"xname"`
+
+	// "create", "update" or "delete".
+	Action string `json:"action"`
+
+	// The JSON names of the fields set from the spec, never their values,
+	// and of those that get generated passwords.
+	Fields   []string `json:"fields,omitempty"`
+	Generate []string `json:"generate,omitempty"`
+
+	// Set by Apply if the change failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Plan is the set of changes that make a store match a spec.
+type Plan struct {
+	Store   *cc.CompCredStore
+	Changes []Change
+
+	components map[string]Component
+	secrets    map[string]map[string]string
+}
+
+// Compare a spec with the stored credentials and plan the changes that make
+// them match. Secret references are read now; passwords are generated by
+// Apply.
+func NewPlan(ccs *cc.CompCredStore, spec *Spec) (*Plan, error) {
+	stored, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list credentials: %v", err)
+	}
+	delete(stored, "")
+
+	p := &Plan{
+		Store:      ccs,
+		components: make(map[string]Component, len(spec.Components)),
+		secrets:    make(map[string]map[string]string, len(spec.Components)),
+	}
+	for _, comp := range spec.Components {
+		values := make(map[string]string)
+		for name, s := range comp.secrets() {
+			if *s == nil || (*s).Ref == "" {
+				continue
+			}
+			if values[name], err = spec.resolve((*s).Ref); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", comp.Xname, name, err)
+			}
+		}
+		p.components[comp.Xname] = comp
+		p.secrets[comp.Xname] = values
+
+		existing, ok := stored[comp.Xname]
+		change := Change{Xname: comp.Xname, Action: "update"}
+		if !ok {
+			existing = cc.CompCredentials{Xname: comp.Xname}
+			change.Action = "create"
+		}
+		cred, generate, err := desiredCred(existing, comp, values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", comp.Xname, err)
+		}
+		change.Fields = cc.ChangedFields(existing, cred)
+		change.Generate = generate
+		if ok && len(change.Fields) == 0 && len(change.Generate) == 0 {
+			continue
+		}
+		p.Changes = append(p.Changes, change)
+	}
+
+	if spec.Prune {
+		for xname := range stored {
+			if _, ok := p.components[xname]; !ok {
+				p.Changes = append(p.Changes, Change{Xname: xname, Action: "delete"})
+			}
+		}
+	}
+	sort.Slice(p.Changes, func(i, j int) bool { return p.Changes[i].Xname < p.Changes[j].Xname })
+	return p, nil
+}
+
+// Make the planned changes, recording any failure in the change's Error.
+// Each component's credentials are read again first, so fields the spec
+// does not manage keep any value stored since the plan was made. A failure
+// on one component does not stop the others; an error is returned after
+// all have been tried.
+func (p *Plan) Apply() error {
+	var failed []string
+	for i := range p.Changes {
+		change := &p.Changes[i]
+		if err := p.apply(change); err != nil {
+			change.Error = err.Error()
+			failed = append(failed, change.Xname)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to apply changes to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (p *Plan) apply(change *Change) error {
+	if change.Action == "delete" {
+		return p.Store.DeleteCompCred(change.Xname)
+	}
+
+	existing, err := p.Store.GetCompCred(change.Xname)
+	if err != nil {
+		return err
+	}
+	existing.Xname = change.Xname
+	comp := p.components[change.Xname]
+	cred, generate, err := desiredCred(existing, comp, p.secrets[change.Xname])
+	if err != nil {
+		return err
+	}
+	for _, name := range generate {
+		var policy cc.PasswordPolicy
+		if s := *comp.secrets()[name]; s.Policy != nil {
+			policy = *s.Policy
+		}
+		field, err := credField(&cred, name)
+		if err != nil {
+			return err
+		}
+		if *field, err = cc.GeneratePassword(policy); err != nil {
+			return fmt.Errorf("unable to generate %s: %v", name, err)
+		}
+	}
+	return p.Store.StoreCompCred(cred)
+}
+
+// Make the store match a spec, and return the plan that was applied.
+func Apply(ccs *cc.CompCredStore, spec *Spec) (*Plan, error) {
+	p, err := NewPlan(ccs, spec)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.Apply()
+}
+
+// Get the credentials a component should have, given those stored and its
+// resolved secret references, and the secret fields that need generated
+// passwords because none are stored.
+func desiredCred(existing cc.CompCredentials, comp Component, values map[string]string) (cc.CompCredentials, []string, error) {
+	cred := existing
+	if comp.URL != "" {
+		cred.URL = comp.URL
+	}
+	if comp.Username != "" {
+		cred.Username = comp.Username
+	}
+	if comp.SSHHostKey != "" {
+		cred.SSHHostKey = comp.SSHHostKey
+	}
+
+	var generate []string
+	secrets := comp.secrets()
+	for _, name := range secretFields {
+		s := *secrets[name]
+		field, err := credField(&cred, name)
+		if err != nil {
+			return cred, nil, err
+		}
+		switch {
+		case s == nil:
+		case s.Ref != "":
+			*field = values[name]
+		case *field == "":
+			generate = append(generate, name)
+		}
+	}
+	return cred, generate, nil
+}
+
+// Get the field of a set of credentials with the given JSON name.
+func credField(cred *cc.CompCredentials, name string) (*string, error) {
+	switch name {
+	case "password":
+		return &cred.Password, nil
+	case "SNMPAuthPass":
+		return &cred.SNMPAuthPass, nil
+	case "SNMPPrivPass":
+		return &cred.SNMPPrivPass, nil
+	case "SSHKey":
+		return &cred.SSHKey, nil
+	}
+	return nil, fmt.Errorf("unknown secret field %q", name)
+}
@@ -0,0 +1,103 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FallbackStats counts credentials read from a CompCredStore's fallback
+// paths, to follow a layout change as records migrate.
+type FallbackStats struct {
+	// Reads served from a fallback path.
+	Hits int64 `json:"hits"`
+
+	// Credentials stored under CCPath by MigrateOnRead, and those that
+	// could not be.
+	Migrated      int64 `json:"migrated"`
+	MigrateFailed int64 `json:"migrate_failed"`
+}
+
+type fallbackCounters struct {
+	hits, migrated, migrateFailed atomic.Int64
+}
+
+// Get the counts of credentials read from fallback paths since the store
+// was created.
+func (ccs *CompCredStore) FallbackStats() FallbackStats {
+	return FallbackStats{
+		Hits:          ccs.fallback.hits.Load(),
+		Migrated:      ccs.fallback.migrated.Load(),
+		MigrateFailed: ccs.fallback.migrateFailed.Load(),
+	}
+}
+
+// Read credentials not under CCPath from the first fallback path holding
+// them, and with MigrateOnRead, store them under CCPath with their metadata.
+// A failure to store them is logged rather than failing the read.
+func (ccs *CompCredStore) lookupFallback(xname string) (CompCredentials, error) {
+	for _, path := range ccs.FallbackPaths {
+		var compCred CompCredentials
+		if err := ccs.SS.Lookup(path+"/"+xname, &compCred); err != nil {
+			return compCred, err
+		}
+		if compCred.Xname == "" {
+			continue
+		}
+
+		ccs.fallback.hits.Add(1)
+		if ccs.MigrateOnRead {
+			if err := ccs.storeCompCred(compCred, "", storeCopied); err != nil {
+				ccs.fallback.migrateFailed.Add(1)
+				log.WithError(err).WithFields(log.Fields{"xname": xname, "path": path}).
+					Warn("Unable to migrate credentials from fallback path")
+			} else {
+				ccs.fallback.migrated.Add(1)
+			}
+		}
+		return compCred, nil
+	}
+	return CompCredentials{}, nil
+}
+
+// List the keys under CCPath and the fallback paths, once each. CCPath may
+// have no keys yet, early in a layout change.
+func (ccs *CompCredStore) fallbackKeys() ([]string, error) {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, path := range append([]string{ccs.CCPath}, ccs.FallbackPaths...) {
+		pathKeys, err := LookupKeys(ccs.SS, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range pathKeys {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,113 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+// A store under "hms-creds-v2" falling back to "hms-creds-old" and then
+// "hms-creds", which both have x0c0s2b0.
+func fallbackStore(t *testing.T, migrate bool) (*cc.CompCredStore, *failingStorage) {
+	fs := &failingStorage{MemoryStorage: conformance.NewMemoryStorage()}
+	legacy := cc.NewCompCredStore(cc.DefaultCompCredPath, fs)
+	for _, cred := range bundleCreds[1:] {
+		legacy.StoreCompCred(cred)
+	}
+	older := bundleCreds[1]
+	older.Password = "older"
+	cc.NewCompCredStore("hms-creds-old", fs).StoreCompCred(older)
+
+	ccs := cc.NewCompCredStore("hms-creds-v2", fs)
+	ccs.StoreCompCred(bundleCreds[0])
+	ccs.FallbackPaths = []string{"hms-creds-old", cc.DefaultCompCredPath}
+	ccs.MigrateOnRead = migrate
+	return ccs, fs
+}
+
+func TestFallbackRead(t *testing.T) {
+	var tests = []struct {
+		migrate  bool
+		xname    string
+		password string
+		stats    cc.FallbackStats
+	}{
+		{false, "x0c0s1b0", "secret-one", cc.FallbackStats{}},
+		{false, "x0c0s2b0", "older", cc.FallbackStats{Hits: 1}},
+		{false, "x0c0s3b0", "secret-three", cc.FallbackStats{Hits: 1}},
+		{false, "x0c0s9b0", "", cc.FallbackStats{}},
+		{true, "x0c0s3b0", "secret-three", cc.FallbackStats{Hits: 1, Migrated: 1}},
+	}
+
+	for i, test := range tests {
+		ccs, fs := fallbackStore(t, test.migrate)
+		cred, err := ccs.GetCompCred(test.xname)
+		if err != nil || cred.Password != test.password {
+			t.Errorf("Test %v Failed: Expected password %q but got %q, %v", i, test.password, cred.Password, err)
+		}
+		if stats := ccs.FallbackStats(); stats != test.stats {
+			t.Errorf("Test %v Failed: Expected %+v but got %+v", i, test.stats, stats)
+		}
+		primary := cc.NewCompCredStore("hms-creds-v2", fs)
+		if cred, _ := primary.GetCompCred(test.xname); (cred.Xname != "") != (test.migrate || test.xname == "x0c0s1b0") {
+			t.Errorf("Test %v Failed: Unexpected primary record %v", i, cred)
+		}
+	}
+}
+
+func TestFallbackMigrateFailure(t *testing.T) {
+	ccs, fs := fallbackStore(t, true)
+	fs.fail = "hms-creds-v2/"
+	if cred, err := ccs.GetCompCred("x0c0s3b0"); err != nil || cred.Password != "secret-three" {
+		t.Errorf("Expected the read to succeed but got %v, %v", cred, err)
+	}
+	if stats := ccs.FallbackStats(); stats != (cc.FallbackStats{Hits: 1, MigrateFailed: 1}) {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestFallbackAllAndDelete(t *testing.T) {
+	ccs, fs := fallbackStore(t, false)
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil || len(creds) != 3 || creds["x0c0s2b0"].Password != "older" {
+		t.Errorf("Expected credentials from every path but got %v, %v", creds, err)
+	}
+
+	// An empty primary path, as at the start of a layout change.
+	empty := cc.NewCompCredStore("hms-creds-v3", fs)
+	empty.FallbackPaths = []string{cc.DefaultCompCredPath}
+	if creds, err := empty.GetAllCompCreds(); err != nil || len(creds) != 2 {
+		t.Errorf("Expected the fallback credentials but got %v, %v", creds, err)
+	}
+
+	// Deleted credentials do not reappear from a fallback path.
+	if err := ccs.DeleteCompCred("x0c0s2b0"); err != nil {
+		t.Fatalf("DeleteCompCred failed: %v", err)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s2b0"); cred.Xname != "" {
+		t.Errorf("Expected x0c0s2b0 to be deleted from every path but got %v", cred)
+	}
+}
@@ -0,0 +1,237 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+)
+
+// CredentialVersion describes one kept version of a component's
+// credentials. Versions are numbered from 1 and the highest is the current
+// one, unless it was deleted.
+type CredentialVersion struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Deleted bool      `json:"deleted,omitempty"`
+}
+
+// VersionedStorage is a SecureStorage that keeps past versions of each key,
+// such as KV2Adapter. CompCredStore uses its versions for credential history
+// rather than keeping its own.
+type VersionedStorage interface {
+	sstorage.SecureStorage
+
+	// List the kept versions of a key, oldest first. A key that does not
+	// exist has none.
+	LookupVersions(key string) ([]CredentialVersion, error)
+
+	// Read one version of a key into output. A version that is not kept or
+	// was deleted leaves output untouched.
+	LookupVersion(key string, version int, output interface{}) error
+}
+
+// The form the current version of emulated history is stored in.
+type historyIndex struct {
+	Current int
+	Created string
+}
+
+// The form a past version of emulated history is stored in.
+type historyRecord struct {
+	Version int
+	Created string
+	Cred    CompCredentials
+}
+
+// Get the key space emulated history is kept in. Each xname has an "index"
+// record and its past versions under their numbers.
+func (ccs *CompCredStore) historyPath() string {
+	return ccs.CCPath + "-history"
+}
+
+func (ccs *CompCredStore) historyKey(xname, name string) string {
+	return ccs.historyPath() + "/" + xname + "/" + name
+}
+
+// Get the backend as a VersionedStorage, if it is one.
+func (ccs *CompCredStore) versioned() (VersionedStorage, bool) {
+	vs, ok := ccs.SS.(VersionedStorage)
+	return vs, ok
+}
+
+// Check whether this store keeps its own credential history.
+func (ccs *CompCredStore) emulateHistory() bool {
+	_, ok := ccs.versioned()
+	return ccs.HistoryLimit > 0 && !ok
+}
+
+// Record the credentials about to be replaced in the emulated history, and
+// return the index to store once the new ones are.
+func (ccs *CompCredStore) recordHistory(xname string) (historyIndex, error) {
+	var index historyIndex
+	if err := ccs.SS.Lookup(ccs.historyKey(xname, "index"), &index); err != nil {
+		return index, err
+	}
+	var existing CompCredentials
+	if err := ccs.SS.Lookup(ccs.CCPath+"/"+xname, &existing); err != nil {
+		return index, err
+	}
+	if existing.Xname != "" {
+		// Credentials stored before history was kept are version 1.
+		if index.Current == 0 {
+			index.Current = 1
+		}
+		record := historyRecord{Version: index.Current, Created: index.Created, Cred: existing}
+		if err := ccs.SS.Store(ccs.historyKey(xname, strconv.Itoa(index.Current)), record); err != nil {
+			return index, fmt.Errorf("unable to record history for %s: %v", xname, err)
+		}
+	}
+
+	// Drop versions beyond the limit, including after it was lowered.
+	for v := index.Current - ccs.HistoryLimit; v > 0; v-- {
+		key := ccs.historyKey(xname, strconv.Itoa(v))
+		var old historyRecord
+		if err := ccs.SS.Lookup(key, &old); err != nil {
+			return index, err
+		}
+		if old.Version == 0 {
+			break
+		}
+		if err := ccs.SS.Delete(key); err != nil {
+			return index, err
+		}
+	}
+
+	index.Current++
+	index.Created = time.Now().UTC().Format(time.RFC3339Nano)
+	return index, nil
+}
+
+// Remove the emulated history of a component.
+func (ccs *CompCredStore) deleteHistory(xname string) error {
+	keys, err := LookupKeys(ccs.SS, ccs.historyPath()+"/"+xname)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := ccs.SS.Delete(ccs.historyKey(xname, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List the kept versions of a component's credentials, oldest first. The
+// versions come from a VersionedStorage backend, or else from the history
+// kept with HistoryLimit.
+func (ccs *CompCredStore) ListCompCredVersions(xname string) ([]CredentialVersion, error) {
+	if vs, ok := ccs.versioned(); ok {
+		return vs.LookupVersions(ccs.CCPath + "/" + xname)
+	}
+	if ccs.HistoryLimit <= 0 {
+		return nil, fmt.Errorf("%s keeps no credential history", ccs.CCPath)
+	}
+
+	keys, err := LookupKeys(ccs.SS, ccs.historyPath()+"/"+xname)
+	if err != nil {
+		return nil, err
+	}
+	var versions []CredentialVersion
+	for _, key := range keys {
+		if key == "index" {
+			continue
+		}
+		var record historyRecord
+		if err := ccs.SS.Lookup(ccs.historyKey(xname, key), &record); err != nil {
+			return nil, err
+		}
+		if record.Version != 0 {
+			created, _ := time.Parse(time.RFC3339Nano, record.Created)
+			versions = append(versions, CredentialVersion{Version: record.Version, Created: created})
+		}
+	}
+
+	current, err := ccs.GetCompCred(xname)
+	if err != nil {
+		return nil, err
+	}
+	if current.Xname != "" {
+		var index historyIndex
+		if err := ccs.SS.Lookup(ccs.historyKey(xname, "index"), &index); err != nil {
+			return nil, err
+		}
+		if index.Current == 0 {
+			index.Current = 1
+		}
+		created, _ := time.Parse(time.RFC3339Nano, index.Created)
+		versions = append(versions, CredentialVersion{Version: index.Current, Created: created})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// Get one version of a component's credentials. The credentials are zero if
+// the version is not kept.
+func (ccs *CompCredStore) GetCompCredVersion(xname string, version int) (CompCredentials, error) {
+	var cred CompCredentials
+	if vs, ok := ccs.versioned(); ok {
+		err := vs.LookupVersion(ccs.CCPath+"/"+xname, version, &cred)
+		return cred, err
+	}
+	if ccs.HistoryLimit <= 0 {
+		return cred, fmt.Errorf("%s keeps no credential history", ccs.CCPath)
+	}
+
+	var index historyIndex
+	if err := ccs.SS.Lookup(ccs.historyKey(xname, "index"), &index); err != nil {
+		return cred, err
+	}
+	if version == index.Current || (index.Current == 0 && version == 1) {
+		return ccs.GetCompCred(xname)
+	}
+	var record historyRecord
+	if err := ccs.SS.Lookup(ccs.historyKey(xname, strconv.Itoa(version)), &record); err != nil {
+		return cred, err
+	}
+	return record.Cred, nil
+}
+
+// Store an earlier version of a component's credentials as the current
+// one, such as after a rotation goes wrong, and return them. The rollback
+// is itself a new version, so it can be undone the same way, and is not
+// refused as password reuse.
+func (ccs *CompCredStore) RollbackCompCred(xname string, version int) (CompCredentials, error) {
+	cred, err := ccs.GetCompCredVersion(xname, version)
+	if err != nil {
+		return cred, err
+	}
+	if cred.Xname == "" {
+		return cred, fmt.Errorf("version %d of %s is not kept", version, xname)
+	}
+	return cred, ccs.storeCompCred(cred, "", storeRolledBack)
+}
@@ -0,0 +1,111 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"reflect"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Store passwords "one", "two" and so on in turn for x0c0s1b0.
+func storePasswords(t *testing.T, ccs *cc.CompCredStore, passwords ...string) {
+	for _, password := range passwords {
+		if err := ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: password}); err != nil {
+			t.Fatalf("StoreCompCred failed: %v", err)
+		}
+	}
+}
+
+func versionNumbers(versions []cc.CredentialVersion) []int {
+	var nums []int
+	for _, v := range versions {
+		nums = append(nums, v.Version)
+	}
+	return nums
+}
+
+func TestEmulatedHistory(t *testing.T) {
+	ccs := newBundleStore(t)
+	if _, err := ccs.ListCompCredVersions("x0c0s1b0"); err == nil {
+		t.Errorf("Expected an error without HistoryLimit")
+	}
+
+	// Credentials stored before history was kept are version 1.
+	storePasswords(t, ccs, "one")
+	ccs.HistoryLimit = 2
+	storePasswords(t, ccs, "two", "three", "four")
+
+	var tests = []struct {
+		version  int
+		password string
+	}{
+		{1, ""},
+		{2, "two"},
+		{3, "three"},
+		{4, "four"},
+		{5, ""},
+	}
+	for i, test := range tests {
+		cred, err := ccs.GetCompCredVersion("x0c0s1b0", test.version)
+		if err != nil || cred.Password != test.password {
+			t.Errorf("Test %v Failed: Expected password %q but got %q, %v", i, test.password, cred.Password, err)
+		}
+	}
+
+	versions, err := ccs.ListCompCredVersions("x0c0s1b0")
+	if err != nil || !reflect.DeepEqual(versionNumbers(versions), []int{2, 3, 4}) {
+		t.Errorf("Expected versions 2 to 4 but got %v, %v", versions, err)
+	}
+	if versions[2].Created.IsZero() || versions[2].Created.Before(versions[0].Created) {
+		t.Errorf("Unexpected version times %v", versions)
+	}
+
+	cred, err := ccs.RollbackCompCred("x0c0s1b0", 2)
+	if err != nil || cred.Password != "two" {
+		t.Fatalf("Expected a rollback to version 2 but got %v, %v", cred, err)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "two" {
+		t.Errorf("Expected the rolled back password but got %q", cred.Password)
+	}
+	if versions, _ := ccs.ListCompCredVersions("x0c0s1b0"); !reflect.DeepEqual(versionNumbers(versions), []int{3, 4, 5}) {
+		t.Errorf("Expected versions 3 to 5 after the rollback but got %v", versions)
+	}
+	if _, err := ccs.RollbackCompCred("x0c0s1b0", 1); err == nil {
+		t.Errorf("Expected an error rolling back to a version no longer kept")
+	}
+
+	// Deleted credentials take their history with them.
+	ccs.DeleteCompCred("x0c0s1b0")
+	if versions, err := ccs.ListCompCredVersions("x0c0s1b0"); err != nil || len(versions) != 0 {
+		t.Errorf("Expected no versions after delete but got %v, %v", versions, err)
+	}
+	if keys, _ := ccs.SS.LookupKeys(ccs.CCPath + "-history"); len(keys) != 0 {
+		t.Errorf("Expected the history to be removed but got %v", keys)
+	}
+	storePasswords(t, ccs, "again")
+	if versions, _ := ccs.ListCompCredVersions("x0c0s1b0"); !reflect.DeepEqual(versionNumbers(versions), []int{1}) {
+		t.Errorf("Expected a new first version but got %v", versions)
+	}
+}
@@ -0,0 +1,189 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+	"github.com/hashicorp/vault/api"
+	"github.com/mitchellh/mapstructure"
+)
+
+// KV2Adapter is a SecureStorage for a Vault KV version 2 secrets engine,
+// which keeps past versions of each secret. The hms-securestorage
+// VaultAdapter reads and writes KV version 1 paths; KV2Adapter uses its
+// connection, token refresh and retries with the version 2 "data" and
+// "metadata" paths. Its BasePath must be the engine's mount path.
+//
+// Delete removes every version of a key, as Delete on an unversioned store
+// leaves nothing behind.
+type KV2Adapter struct {
+	Vault *sstorage.VaultAdapter
+}
+
+// Create a KV2Adapter using a VaultAdapter's connection.
+func NewKV2Adapter(va *sstorage.VaultAdapter) *KV2Adapter {
+	return &KV2Adapter{Vault: va}
+}
+
+// The "data" field of a KV version 2 read.
+type kv2Data struct {
+	Data map[string]interface{} `mapstructure:"data"`
+}
+
+// The parts of KV version 2 metadata used for versions.
+type kv2Metadata struct {
+	Versions map[string]struct {
+		CreatedTime  string `mapstructure:"created_time"`
+		DeletionTime string `mapstructure:"deletion_time"`
+		Destroyed    bool   `mapstructure:"destroyed"`
+	} `mapstructure:"versions"`
+}
+
+// Wrap a value in the form KV version 2 writes take.
+func kv2Value(value interface{}) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := mapstructure.Decode(value, &data); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"data": data}, nil
+}
+
+// Store a value at key as its new version.
+func (kv *KV2Adapter) Store(key string, value interface{}) error {
+	wrapped, err := kv2Value(value)
+	if err != nil {
+		return err
+	}
+	return kv.Vault.Store("data/"+key, wrapped)
+}
+
+// Store a value at key as its new version, and decode the response, which
+// describes the version, into output.
+func (kv *KV2Adapter) StoreWithData(key string, value interface{}, output interface{}) error {
+	wrapped, err := kv2Value(value)
+	if err != nil {
+		return err
+	}
+	return kv.Vault.StoreWithData("data/"+key, wrapped, output)
+}
+
+// Read the current version of key into output. A key that does not exist,
+// or whose current version was deleted, leaves output untouched.
+func (kv *KV2Adapter) Lookup(key string, output interface{}) error {
+	if output == nil {
+		return fmt.Errorf("output interface was nil")
+	}
+	var secret kv2Data
+	if err := kv.Vault.Lookup("data/"+key, &secret); err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		return nil
+	}
+	return mapstructure.Decode(secret.Data, output)
+}
+
+// Remove key and all of its versions.
+func (kv *KV2Adapter) Delete(key string) error {
+	return kv.Vault.Delete("metadata/" + key)
+}
+
+// List the immediate children of keyPath. A path with nothing under it has
+// none.
+func (kv *KV2Adapter) LookupKeys(keyPath string) ([]string, error) {
+	return LookupKeys(kv.Vault, "metadata/"+keyPath)
+}
+
+// List the kept versions of key, oldest first.
+func (kv *KV2Adapter) LookupVersions(key string) ([]CredentialVersion, error) {
+	var md kv2Metadata
+	if err := kv.Vault.Lookup("metadata/"+key, &md); err != nil {
+		return nil, err
+	}
+	versions := make([]CredentialVersion, 0, len(md.Versions))
+	for n, v := range md.Versions {
+		version, err := strconv.Atoi(n)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected version %q of %s", n, key)
+		}
+		created, _ := time.Parse(time.RFC3339Nano, v.CreatedTime)
+		versions = append(versions, CredentialVersion{
+			Version: version,
+			Created: created,
+			Deleted: v.DeletionTime != "" || v.Destroyed,
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// Read one version of key into output. A version that is not kept or was
+// deleted leaves output untouched.
+func (kv *KV2Adapter) LookupVersion(key string, version int, output interface{}) error {
+	if output == nil {
+		return fmt.Errorf("output interface was nil")
+	}
+	secret, err := kv.readVersion("data/"+key, version)
+	if err != nil {
+		// The VaultAdapter cannot be asked to read a version, so it cannot
+		// refresh an expired token for this read. Reading the metadata
+		// through it does, and the read is tried once more.
+		var md kv2Metadata
+		if kv.Vault.Lookup("metadata/"+key, &md) != nil {
+			return err
+		}
+		if secret, err = kv.readVersion("data/"+key, version); err != nil {
+			return err
+		}
+	}
+	if secret == nil {
+		return nil
+	}
+	var data kv2Data
+	if err := mapstructure.Decode(secret.Data, &data); err != nil {
+		return err
+	}
+	if data.Data == nil {
+		return nil
+	}
+	return mapstructure.Decode(data.Data, output)
+}
+
+func (kv *KV2Adapter) readVersion(key string, version int) (*api.Secret, error) {
+	params := map[string][]string{"version": {strconv.Itoa(version)}}
+	path := kv.Vault.BasePath + "/" + key
+	switch client := kv.Vault.Client.(type) {
+	case *sstorage.RealVaultApi:
+		return client.Client.Logical().ReadWithData(path, params)
+	case interface {
+		ReadWithData(string, map[string][]string) (*api.Secret, error)
+	}:
+		return client.ReadWithData(path, params)
+	}
+	return nil, fmt.Errorf("the Vault client cannot read versions")
+}
@@ -0,0 +1,137 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+package compcredentials
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The error StoreCompCred wraps when credentials have an Expires time that
+// is not in RFC 3339 format.
+var ErrInvalidExpiry = errors.New("invalid expiry time")
+
+// MetadataPolicy makes StoreCompCred record when and by whom credentials
+// are created and changed, and optionally when they are due for rotation.
+type MetadataPolicy struct {
+	// Recorded as UpdatedBy for credentials stored by StoreCompCred
+	// rather than StoreCompCredAs.
+	Updater string
+
+	// Credentials that change without being given a new Expires time
+	// expire this long after the change. Zero leaves them without one.
+	MaxAge time.Duration
+}
+
+// Set the metadata of credentials about to be stored. Created is kept from
+// the stored credentials, and Updated and UpdatedBy change only when the
+// credentials do, so storing them again does not reset their age. An
+// Expires time that differs from the stored one is the caller's and is
+// kept; otherwise a change replaces the stored one according to MaxAge.
+// Credentials copied or restored with keep set keep the metadata they carry.
+func (ccs *CompCredStore) stampMetadata(cred *CompCredentials, updater string, keep bool) error {
+	if cred.Expires != "" {
+		if _, err := time.Parse(time.RFC3339, cred.Expires); err != nil {
+			return fmt.Errorf("%w %q for %s: %v", ErrInvalidExpiry, cred.Expires, cred.Xname, err)
+		}
+	}
+	policy := ccs.Metadata
+	if policy == nil || keep {
+		return nil
+	}
+
+	var existing CompCredentials
+	if err := ccs.SS.Lookup(ccs.CCPath+"/"+cred.Xname, &existing); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if updater == "" {
+		updater = policy.Updater
+	}
+
+	cred.Created = existing.Created
+	if existing.Xname == "" {
+		cred.Created = now.Format(time.RFC3339)
+	}
+	if existing.Xname != "" && len(ChangedFields(existing, *cred)) == 0 {
+		cred.Updated = existing.Updated
+		cred.UpdatedBy = existing.UpdatedBy
+		if cred.Expires == "" {
+			cred.Expires = existing.Expires
+		}
+		return nil
+	}
+	cred.Updated = now.Format(time.RFC3339)
+	cred.UpdatedBy = updater
+	if cred.Expires == "" || cred.Expires == existing.Expires {
+		cred.Expires = ""
+		if policy.MaxAge > 0 {
+			cred.Expires = now.Add(policy.MaxAge).Format(time.RFC3339)
+		}
+	}
+	return nil
+}
+
+// Report whether the credentials have an Expires time at or before now.
+// An Expires time that cannot be parsed counts as past.
+func (compCred CompCredentials) Expired(now time.Time) bool {
+	if compCred.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse(time.RFC3339, compCred.Expires)
+	return err != nil || !expires.After(now)
+}
+
+// Get how long before now the credentials last changed. Returns false if
+// that is not known, such as for credentials stored without a
+// MetadataPolicy.
+func (compCred CompCredentials) Age(now time.Time) (time.Duration, bool) {
+	updated, err := time.Parse(time.RFC3339, compCred.Updated)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(updated), true
+}
+
+// Get the credentials due for rotation: those past their Expires time and,
+// if maxAge is not zero, those that last changed more than maxAge ago or
+// whose age is not known.
+func (ccs *CompCredStore) GetRotationDueCompCreds(maxAge time.Duration) (map[string]CompCredentials, error) {
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	due := make(map[string]CompCredentials)
+	for xname, cred := range creds {
+		if xname == "" {
+			continue
+		}
+		age, known := cred.Age(now)
+		if cred.Expired(now) || (maxAge > 0 && (!known || age > maxAge)) {
+			due[xname] = cred
+		}
+	}
+	return due, nil
+}
@@ -0,0 +1,233 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+package compcredentials_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+const longAgo = "2020-01-02T03:04:05Z"
+
+func TestMetadata(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	// Stored before metadata was kept.
+	ss.Store(ccs.CCPath+"/x0c0s2b0", cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "old",
+		Created: longAgo, Updated: longAgo, UpdatedBy: "installer"})
+	ccs.Metadata = &cc.MetadataPolicy{Updater: "alice", MaxAge: 24 * time.Hour}
+
+	before := time.Now().Add(-time.Second)
+	if err := ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "123"}); err != nil {
+		t.Fatalf("StoreCompCred failed: %v", err)
+	}
+	cred, _ := ccs.GetCompCred("x0c0s1b0")
+	updated, err := time.Parse(time.RFC3339, cred.Updated)
+	if err != nil || updated.Before(before) || cred.Created != cred.Updated || cred.UpdatedBy != "alice" {
+		t.Fatalf("Expected new credentials to be stamped but got %q %q %q", cred.Created, cred.Updated, cred.UpdatedBy)
+	}
+	if expires, _ := time.Parse(time.RFC3339, cred.Expires); expires.Sub(updated) != 24*time.Hour {
+		t.Errorf("Expected an expiry MaxAge after the update but got %q", cred.Expires)
+	}
+
+	// Storing unchanged credentials does not reset their age.
+	ccs.StoreCompCredAs(cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "123"}, "bob")
+	if again, _ := ccs.GetCompCred("x0c0s1b0"); !reflect.DeepEqual(again, cred) {
+		t.Errorf("Expected %+v to be unchanged but got %+v", cred, again)
+	}
+
+	var tests = []struct {
+		cred      cc.CompCredentials
+		updater   string
+		updatedBy string
+		expires   string
+	}{
+		// Changes are stamped, keeping Created, and get a new expiry.
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "new"}, "bob", "bob", ""},
+		// The caller's own expiry is kept.
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "newer", Expires: "2030-01-01T00:00:00Z"}, "carol", "carol", "2030-01-01T00:00:00Z"},
+		// Changing only the expiry is not a change to the credentials.
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "newer", Expires: "2031-01-01T00:00:00Z"}, "dave", "carol", "2031-01-01T00:00:00Z"},
+	}
+	for i, test := range tests {
+		if err := ccs.StoreCompCredAs(test.cred, test.updater); err != nil {
+			t.Fatalf("Test %v Failed: StoreCompCredAs failed: %v", i, err)
+		}
+		got, _ := ccs.GetCompCred(test.cred.Xname)
+		if got.Created != longAgo || got.Updated == longAgo || got.UpdatedBy != test.updatedBy {
+			t.Errorf("Test %v Failed: Expected Created %v, a new Updated and UpdatedBy %v but got %q, %q, %q",
+				i, longAgo, test.updatedBy, got.Created, got.Updated, got.UpdatedBy)
+		}
+		if test.expires != "" && got.Expires != test.expires {
+			t.Errorf("Test %v Failed: Expected expiry %v but got %q", i, test.expires, got.Expires)
+		}
+		if test.expires == "" && got.Expires == "" {
+			t.Errorf("Test %v Failed: Expected an expiry from MaxAge", i)
+		}
+	}
+
+	if err := ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Expires: "next week"}); err == nil {
+		t.Errorf("Expected an invalid expiry to be refused")
+	}
+	if fields := cc.ChangedFields(cc.CompCredentials{Updated: longAgo}, cc.CompCredentials{Expires: longAgo}); len(fields) != 0 {
+		t.Errorf("Expected metadata not to be compared but got %v", fields)
+	}
+}
+
+func TestRotationDue(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, cred := range []cc.CompCredentials{
+		{Xname: "x0c0s1b0", Password: "fresh", Updated: now},
+		{Xname: "x0c0s2b0", Password: "old", Updated: longAgo},
+		{Xname: "x0c0s3b0", Password: "expired", Updated: now, Expires: longAgo},
+		{Xname: "x0c0s4b0", Password: "unknown"},
+		{Xname: "x0c0s5b0", Password: "later", Updated: now, Expires: "2999-01-01T00:00:00Z"},
+	} {
+		ss.Store(ccs.CCPath+"/"+cred.Xname, cred)
+	}
+
+	var tests = []struct {
+		maxAge time.Duration
+		due    []string
+	}{
+		{0, []string{"x0c0s3b0"}},
+		{90 * 24 * time.Hour, []string{"x0c0s2b0", "x0c0s3b0", "x0c0s4b0"}},
+	}
+	for i, test := range tests {
+		due, err := ccs.GetRotationDueCompCreds(test.maxAge)
+		if err != nil {
+			t.Fatalf("Test %v Failed: GetRotationDueCompCreds failed: %v", i, err)
+		}
+		var xnames []string
+		for _, xname := range []string{"x0c0s1b0", "x0c0s2b0", "x0c0s3b0", "x0c0s4b0", "x0c0s5b0"} {
+			if _, ok := due[xname]; ok {
+				xnames = append(xnames, xname)
+			}
+		}
+		if len(due) != len(xnames) || !reflect.DeepEqual(xnames, test.due) {
+			t.Errorf("Test %v Failed: Expected %v to be due but got %v", i, test.due, due)
+		}
+	}
+}
+
+func TestMetadataCopies(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	legacy := cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "old",
+		Created: longAgo, Updated: longAgo, UpdatedBy: "installer"}
+	ss.Store("hms-creds-v1/x0c0s1b0", legacy)
+	policy := &cc.MetadataPolicy{Updater: "alice", MaxAge: 90 * 24 * time.Hour}
+
+	// Migrating a record as it is read must not reset its age.
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	ccs.FallbackPaths = []string{"hms-creds-v1"}
+	ccs.MigrateOnRead = true
+	ccs.Metadata = policy
+	for i := 0; i < 2; i++ {
+		due, err := ccs.GetRotationDueCompCreds(90 * 24 * time.Hour)
+		if err != nil || len(due) != 1 {
+			t.Errorf("Pass %v: Expected the legacy credentials to be due but got %v, %v", i, due, err)
+		}
+	}
+	if stats := ccs.FallbackStats(); stats.Migrated != 1 {
+		t.Errorf("Expected the credentials to be migrated but got %+v", stats)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); !reflect.DeepEqual(cred, legacy) {
+		t.Errorf("Expected the credentials to be migrated with their metadata but got %q, %q, %q", cred.Created, cred.Updated, cred.UpdatedBy)
+	}
+
+	// Nor must migrating or syncing them to another store.
+	for i, copy := range []func(dst *cc.CompCredStore) error{
+		func(dst *cc.CompCredStore) error {
+			_, err := cc.Migrate(ccs, dst, cc.MigrateOptions{})
+			return err
+		},
+		func(dst *cc.CompCredStore) error {
+			_, err := cc.Sync(ccs, dst, cc.SyncOptions{})
+			return err
+		},
+	} {
+		dst := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+		dst.Metadata = policy
+		if err := copy(dst); err != nil {
+			t.Fatalf("Test %v Failed: Unable to copy credentials: %v", i, err)
+		}
+		if cred, _ := dst.GetCompCred("x0c0s1b0"); !reflect.DeepEqual(cred, legacy) {
+			t.Errorf("Test %v Failed: Expected the credentials to be copied with their metadata but got %q, %q, %q", i, cred.Created, cred.Updated, cred.UpdatedBy)
+		}
+	}
+}
+
+func TestMetadataRestores(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	old := cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "old",
+		Created: longAgo, Updated: longAgo, UpdatedBy: "installer"}
+	ss.Store(cc.DefaultCompCredPath+"/x0c0s1b0", old)
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	ccs.Metadata = &cc.MetadataPolicy{Updater: "alice", MaxAge: 90 * 24 * time.Hour}
+
+	s, err := ccs.CurrentSnapshot("before", "")
+	if err != nil {
+		t.Fatalf("CurrentSnapshot failed: %v", err)
+	}
+	key := cc.BundleKey{Passphrase: "correct horse battery staple"}
+	var bundle bytes.Buffer
+	if _, err := ccs.Export(&bundle, key); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// Restoring a 200-day-old password, over a newer one or into an empty
+	// store, must not make it look freshly rotated.
+	for i, restore := range []func(ccs *cc.CompCredStore) error{
+		func(ccs *cc.CompCredStore) error {
+			_, err := ccs.RestoreSnapshot(s, nil, false)
+			return err
+		},
+		func(ccs *cc.CompCredStore) error {
+			_, err := ccs.Import(bytes.NewReader(bundle.Bytes()), key, cc.ImportOptions{Conflict: cc.ConflictOverwrite})
+			return err
+		},
+	} {
+		for _, rotated := range []bool{true, false} {
+			if rotated {
+				ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "new"})
+			} else {
+				ccs.DeleteCompCred("x0c0s1b0")
+			}
+			if err := restore(ccs); err != nil {
+				t.Fatalf("Test %v Failed: Unable to restore credentials: %v", i, err)
+			}
+			if cred, _ := ccs.GetCompCred("x0c0s1b0"); !reflect.DeepEqual(cred, old) {
+				t.Errorf("Test %v Failed: Expected the credentials to be restored with their metadata but got %q, %q, %q", i, cred.Created, cred.Updated, cred.UpdatedBy)
+			}
+			if due, err := ccs.GetRotationDueCompCreds(90 * 24 * time.Hour); err != nil || len(due) != 1 {
+				t.Errorf("Test %v Failed: Expected the restored credentials to be due but got %v, %v", i, due, err)
+			}
+		}
+	}
+}
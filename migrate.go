@@ -0,0 +1,292 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// A MigrateTransform rewrites one record as Migrate copies it. The record is
+// in the form the source SecureStorage returns it, so a transform can
+// upgrade an older schema before the record is decoded. The xname is the
+// record's key under the source path, and a transform can return another
+// to map records to a new layout. Returning a nil record skips it.
+type MigrateTransform func(xname string, record map[string]interface{}) (string, map[string]interface{}, error)
+
+// MigrateOptions control Migrate.
+type MigrateOptions struct {
+	// Applied in order to each record.
+	Transforms []MigrateTransform
+
+	// The name progress is recorded under, in the destination's
+	// "<path>-migrations" key space, so an interrupted migration can be run
+	// again and carry on where it stopped. Empty records no progress.
+	Checkpoint string
+
+	// Remove each migrated record from the source once every record has
+	// been migrated and the destination still holds it.
+	DeleteSource bool
+
+	// Report what would be migrated without changing either store.
+	DryRun bool
+}
+
+// MigrateResult describes what Migrate did, or would do, with one source
+// record. Change is "migrated", "done" if the checkpoint shows an earlier
+// run migrated it, or "skipped" if a transform dropped it. To is the
+// destination xname if a transform changed it, and Error is set if the
+// record failed.
+type MigrateResult struct {
+	Xname   string `json:"xname"`
+	To      string `json:"to,omitempty"`
+	Change  string `json:"change"`
+	Deleted bool   `json:"deleted,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// The form a migration's progress is stored in. Source xnames are migrated
+// in order, so only the last one tried and those that failed are recorded.
+type migrateCheckpoint struct {
+	Source  string
+	Last    string
+	Failed  []string
+	Updated string
+}
+
+// Rename fields of a record, such as to upgrade an older schema. The map is
+// from the old name to the new one.
+func RenameFields(names map[string]string) MigrateTransform {
+	return func(xname string, record map[string]interface{}) (string, map[string]interface{}, error) {
+		for from, to := range names {
+			if v, ok := record[from]; ok {
+				delete(record, from)
+				record[to] = v
+			}
+		}
+		return xname, record, nil
+	}
+}
+
+// Copy every record from one store to another, applying transforms, and
+// read each back from the destination to verify it. A failure on one record
+// does not stop the others; its result has Error set, and an error is
+// returned after all have been tried. Only if none failed is the source
+// deleted, when asked for, and the checkpoint removed.
+func Migrate(src, dst *CompCredStore, opts MigrateOptions) ([]MigrateResult, error) {
+	if src.SS == dst.SS && src.CCPath == dst.CCPath {
+		return nil, fmt.Errorf("the source and destination are both %s", src.CCPath)
+	}
+	if strings.Contains(opts.Checkpoint, "/") {
+		return nil, fmt.Errorf("checkpoint names cannot contain '/'")
+	}
+
+	keys, err := LookupKeys(src.SS, src.CCPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list credentials in %s: %v", src.CCPath, err)
+	}
+	var xnames []string
+	for _, key := range keys {
+		if !strings.HasSuffix(key, "/") {
+			xnames = append(xnames, key)
+		}
+	}
+	sort.Strings(xnames)
+
+	cp := migrateCheckpoint{Source: src.CCPath}
+	cpKey := dst.CCPath + "-migrations/" + opts.Checkpoint
+	if opts.Checkpoint != "" {
+		var stored migrateCheckpoint
+		if err := dst.SS.Lookup(cpKey, &stored); err != nil {
+			return nil, fmt.Errorf("unable to read checkpoint %s: %v", opts.Checkpoint, err)
+		}
+		if stored.Source != "" && stored.Source != src.CCPath {
+			return nil, fmt.Errorf("checkpoint %s is for a migration from %s", opts.Checkpoint, stored.Source)
+		}
+		if stored.Source != "" {
+			cp = stored
+		}
+	}
+	failed := make(map[string]bool, len(cp.Failed))
+	for _, xname := range cp.Failed {
+		failed[xname] = true
+	}
+
+	var results []MigrateResult
+	for _, xname := range xnames {
+		res := MigrateResult{Xname: xname, Change: "migrated"}
+		if xname <= cp.Last && !failed[xname] {
+			res.Change = "done"
+			results = append(results, res)
+			continue
+		}
+
+		cred, ok, err := migrateRecord(src, xname, opts.Transforms)
+		switch {
+		case err != nil:
+		case !ok:
+			res.Change = "skipped"
+		default:
+			if cred.Xname != xname {
+				res.To = cred.Xname
+			}
+			if !opts.DryRun {
+				err = storeVerified(dst, cred)
+			}
+		}
+		if err != nil {
+			res.Error = err.Error()
+			failed[xname] = true
+		} else {
+			delete(failed, xname)
+		}
+		results = append(results, res)
+
+		if opts.Checkpoint != "" && !opts.DryRun {
+			if xname > cp.Last {
+				cp.Last = xname
+			}
+			cp.Failed = sortedKeys(failed)
+			cp.Updated = time.Now().UTC().Format(time.RFC3339)
+			if err := dst.SS.Store(cpKey, cp); err != nil {
+				return results, fmt.Errorf("unable to record checkpoint %s: %v", opts.Checkpoint, err)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("unable to migrate %d of %d credentials", len(failed), len(results))
+	}
+
+	if opts.DeleteSource {
+		var undeleted int
+		for i := range results {
+			res := &results[i]
+			if res.Change == "skipped" {
+				continue
+			}
+			if opts.DryRun {
+				res.Deleted = true
+				continue
+			}
+			if err := deleteMigrated(src, dst, res.Xname, opts.Transforms); err != nil {
+				res.Error = err.Error()
+				undeleted++
+				continue
+			}
+			res.Deleted = true
+		}
+		if undeleted > 0 {
+			return results, fmt.Errorf("unable to delete %d migrated credentials from %s", undeleted, src.CCPath)
+		}
+	}
+	if opts.Checkpoint != "" && !opts.DryRun {
+		if err := dst.SS.Delete(cpKey); err != nil {
+			return results, fmt.Errorf("unable to remove checkpoint %s: %v", opts.Checkpoint, err)
+		}
+	}
+	return results, nil
+}
+
+// Read one source record and apply the transforms to it. The record is
+// skipped if a transform drops it or it is no longer stored.
+func migrateRecord(src *CompCredStore, xname string, transforms []MigrateTransform) (CompCredentials, bool, error) {
+	var record map[string]interface{}
+	if err := src.SS.Lookup(src.CCPath+"/"+xname, &record); err != nil {
+		return CompCredentials{}, false, err
+	}
+	if record == nil {
+		return CompCredentials{}, false, nil
+	}
+	to := xname
+	for _, transform := range transforms {
+		var err error
+		if to, record, err = transform(to, record); err != nil {
+			return CompCredentials{}, false, err
+		}
+		if record == nil {
+			return CompCredentials{}, false, nil
+		}
+	}
+	if to == "" || strings.Contains(to, "/") {
+		return CompCredentials{}, false, fmt.Errorf("invalid destination xname %q", to)
+	}
+
+	// Fields the transforms leave that CompCredentials does not have are an
+	// error rather than being lost.
+	var cred CompCredentials
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ErrorUnused: true, Result: &cred})
+	if err != nil {
+		return CompCredentials{}, false, err
+	}
+	if err := decoder.Decode(record); err != nil {
+		return CompCredentials{}, false, fmt.Errorf("unable to decode record: %v", err)
+	}
+	cred.Xname = to
+	return cred, true, nil
+}
+
+// Store credentials and check that reading them back gives the same.
+func storeVerified(ccs *CompCredStore, cred CompCredentials) error {
+	if err := ccs.storeCompCred(cred, "", storeCopied); err != nil {
+		return err
+	}
+	stored, err := ccs.GetCompCred(cred.Xname)
+	if err != nil {
+		return fmt.Errorf("unable to read back: %v", err)
+	}
+	if fields := ChangedFields(cred, stored); len(fields) > 0 {
+		return fmt.Errorf("read back differs in %s", strings.Join(fields, ", "))
+	}
+	return nil
+}
+
+// Delete a migrated source record, after checking that the destination
+// still holds what it migrates to, in case either changed since it was
+// copied.
+func deleteMigrated(src, dst *CompCredStore, xname string, transforms []MigrateTransform) error {
+	cred, ok, err := migrateRecord(src, xname, transforms)
+	if err != nil || !ok {
+		return err
+	}
+	stored, err := dst.GetCompCred(cred.Xname)
+	if err != nil {
+		return err
+	}
+	if fields := ChangedFields(cred, stored); len(fields) > 0 {
+		return fmt.Errorf("not deleted as the destination differs in %s", strings.Join(fields, ", "))
+	}
+	return src.DeleteCompCred(xname)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
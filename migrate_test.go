@@ -0,0 +1,169 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+func TestMigrateTransforms(t *testing.T) {
+	src := newBundleStore(t, bundleCreds[1:]...)
+	// A record from before the field was renamed.
+	src.SS.Store(src.CCPath+"/x0c0s1b0", map[string]interface{}{"Xname": "x0c0s1b0", "Username": "root", "Passwd": "old"})
+	dst := cc.NewCompCredStore("hms-creds-v2", conformance.NewMemoryStorage())
+
+	transforms := []cc.MigrateTransform{
+		cc.RenameFields(map[string]string{"Passwd": "Password"}),
+		func(xname string, record map[string]interface{}) (string, map[string]interface{}, error) {
+			if xname == "x0c0s3b0" {
+				return xname, nil, nil
+			}
+			return strings.Replace(xname, "x0", "x1000", 1), record, nil
+		},
+	}
+	if _, err := cc.Migrate(src, src, cc.MigrateOptions{}); err == nil {
+		t.Errorf("Expected an error migrating a store to itself")
+	}
+
+	var tests = []struct {
+		opts    cc.MigrateOptions
+		results []cc.MigrateResult
+		stored  int
+	}{
+		{
+			// Without the schema upgrade the old record is refused.
+			cc.MigrateOptions{DryRun: true, Transforms: transforms[1:]},
+			[]cc.MigrateResult{
+				{Xname: "x0c0s1b0", Change: "migrated", Error: "unable to decode record"},
+				{Xname: "x0c0s2b0", To: "x1000c0s2b0", Change: "migrated"},
+				{Xname: "x0c0s3b0", Change: "skipped"},
+			},
+			0,
+		}, {
+			cc.MigrateOptions{DryRun: true, Transforms: transforms, DeleteSource: true},
+			[]cc.MigrateResult{
+				{Xname: "x0c0s1b0", To: "x1000c0s1b0", Change: "migrated", Deleted: true},
+				{Xname: "x0c0s2b0", To: "x1000c0s2b0", Change: "migrated", Deleted: true},
+				{Xname: "x0c0s3b0", Change: "skipped"},
+			},
+			0,
+		}, {
+			cc.MigrateOptions{Transforms: transforms},
+			[]cc.MigrateResult{
+				{Xname: "x0c0s1b0", To: "x1000c0s1b0", Change: "migrated"},
+				{Xname: "x0c0s2b0", To: "x1000c0s2b0", Change: "migrated"},
+				{Xname: "x0c0s3b0", Change: "skipped"},
+			},
+			2,
+		},
+	}
+
+	for i, test := range tests {
+		results, err := cc.Migrate(src, dst, test.opts)
+		for j := range results {
+			if test.results[j].Error != "" && strings.Contains(results[j].Error, test.results[j].Error) {
+				results[j].Error = test.results[j].Error
+			}
+		}
+		if !reflect.DeepEqual(results, test.results) {
+			t.Errorf("Test %v Failed: Expected %+v but got %+v (%v)", i, test.results, results, err)
+		}
+		if creds, _ := dst.GetAllCompCreds(); len(creds) != test.stored {
+			t.Errorf("Test %v Failed: Expected %v stored credentials but got %v", i, test.stored, len(creds))
+		}
+	}
+	if cred, _ := dst.GetCompCred("x1000c0s1b0"); cred.Password != "old" || cred.Xname != "x1000c0s1b0" {
+		t.Errorf("Expected the upgraded record to be migrated but got %#v", cred)
+	}
+	if creds, _ := src.GetAllCompCreds(); len(creds) != 3 {
+		t.Errorf("Expected the source to be kept but got %v", creds)
+	}
+}
+
+func TestMigrateResume(t *testing.T) {
+	src := newBundleStore(t, bundleCreds...)
+	fs := &failingStorage{MemoryStorage: conformance.NewMemoryStorage(), fail: "x0c0s2b0"}
+	dst := cc.NewCompCredStore("hms-creds-v2", fs)
+	opts := cc.MigrateOptions{Checkpoint: "move", DeleteSource: true}
+
+	results, err := cc.Migrate(src, dst, opts)
+	if err == nil || results[1].Error == "" || results[0].Error != "" || results[2].Error != "" {
+		t.Errorf("Expected only x0c0s2b0 to fail but got %+v, %v", results, err)
+	}
+	if creds, _ := src.GetAllCompCreds(); len(creds) != 3 {
+		t.Errorf("Expected nothing deleted after a failure but %d are left", len(creds))
+	}
+
+	fs.fail = ""
+	results, err = cc.Migrate(src, dst, opts)
+	expected := []cc.MigrateResult{
+		{Xname: "x0c0s1b0", Change: "done", Deleted: true},
+		{Xname: "x0c0s2b0", Change: "migrated", Deleted: true},
+		{Xname: "x0c0s3b0", Change: "done", Deleted: true},
+	}
+	if err != nil || !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected %+v but got %+v, %v", expected, results, err)
+	}
+	if keys, _ := src.SS.LookupKeys(src.CCPath); len(keys) != 0 {
+		t.Errorf("Expected the source to be deleted but got %v", keys)
+	}
+	for _, cred := range bundleCreds {
+		if stored, _ := dst.GetCompCred(cred.Xname); stored != cred {
+			t.Errorf("Expected %v to be migrated but got %v", cred.Xname, stored)
+		}
+	}
+	if keys, _ := fs.LookupKeys("hms-creds-v2-migrations"); len(keys) != 0 {
+		t.Errorf("Expected the checkpoint to be removed but got %v", keys)
+	}
+}
+
+func TestMigrateDeleteChanged(t *testing.T) {
+	src := newBundleStore(t, bundleCreds...)
+	fs := &failingStorage{MemoryStorage: conformance.NewMemoryStorage(), fail: "x0c0s3b0"}
+	dst := cc.NewCompCredStore("hms-creds-v2", fs)
+	opts := cc.MigrateOptions{Checkpoint: "move", DeleteSource: true}
+	if _, err := cc.Migrate(src, dst, opts); err == nil {
+		t.Fatalf("Expected x0c0s3b0 to fail")
+	}
+	// Rotated in the destination before the migration is resumed.
+	fs.fail = ""
+	changed := bundleCreds[0]
+	changed.Password = "rotated"
+	dst.StoreCompCred(changed)
+
+	results, err := cc.Migrate(src, dst, opts)
+	if err == nil || results[0].Deleted || !strings.Contains(results[0].Error, "password") || !results[1].Deleted {
+		t.Errorf("Expected only x0c0s1b0 not to be deleted but got %+v, %v", results, err)
+	}
+	if cred, _ := src.GetCompCred("x0c0s1b0"); cred.Password != "secret-one" {
+		t.Errorf("Expected the source of x0c0s1b0 to be kept")
+	}
+	if cred, _ := dst.GetCompCred("x0c0s1b0"); cred.Password != "rotated" {
+		t.Errorf("Expected the destination of x0c0s1b0 to be kept")
+	}
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// The length of generated passwords when the policy allows it.
+const DefaultPasswordLength = 16
+
+// The special characters generated passwords draw from when a policy does
+// not list its own. They need no quoting in shells, URLs or config files.
+const DefaultSpecialChars = "!#%+-.:=@^_"
+
+const (
+	upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lowerChars = "abcdefghijklmnopqrstuvwxyz"
+	digitChars = "0123456789"
+)
+
+// PasswordPolicy describes the passwords a component accepts. Zero values
+// mean no constraint.
+type PasswordPolicy struct {
+	MinLength      int    `json:"min_length,omitempty"`
+	MaxLength      int    `json:"max_length,omitempty"`
+	RequireUpper   bool   `json:"require_upper,omitempty"`
+	RequireLower   bool   `json:"require_lower,omitempty"`
+	RequireDigit   bool   `json:"require_digit,omitempty"`
+	RequireSpecial bool   `json:"require_special,omitempty"`
+	SpecialChars   string `json:"special_chars,omitempty"`
+}
+
+// Get the special characters allowed by the policy.
+func (p PasswordPolicy) specials() string {
+	if p.SpecialChars != "" {
+		return p.SpecialChars
+	}
+	return DefaultSpecialChars
+}
+
+// Check that a password satisfies the policy.
+func (p PasswordPolicy) Check(password string) error {
+	if p.MinLength > 0 && len(password) < p.MinLength {
+		return fmt.Errorf("password is shorter than %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return fmt.Errorf("password is longer than %d characters", p.MaxLength)
+	}
+	classes := []struct {
+		required bool
+		chars    string
+		name     string
+	}{
+		{p.RequireUpper, upperChars, "an upper case letter"},
+		{p.RequireLower, lowerChars, "a lower case letter"},
+		{p.RequireDigit, digitChars, "a digit"},
+		{p.RequireSpecial, p.specials(), "a special character"},
+	}
+	for _, c := range classes {
+		if c.required && !strings.ContainsAny(password, c.chars) {
+			return fmt.Errorf("password does not contain %s", c.name)
+		}
+	}
+	return nil
+}
+
+// Generate a random password satisfying the policy, DefaultPasswordLength
+// long if the policy allows. Passwords always mix upper and lower case
+// letters and digits, and include special characters when the policy
+// requires them.
+func GeneratePassword(p PasswordPolicy) (string, error) {
+	length := DefaultPasswordLength
+	if p.MinLength > length {
+		length = p.MinLength
+	}
+	if p.MaxLength > 0 && p.MaxLength < length {
+		length = p.MaxLength
+	}
+
+	sets := []string{upperChars, lowerChars, digitChars}
+	if p.RequireSpecial {
+		sets = append(sets, p.specials())
+	}
+	if length < len(sets) {
+		return "", fmt.Errorf("a password of at most %d characters cannot satisfy the policy", length)
+	}
+
+	// One character from each set, the rest from all of them, shuffled.
+	all := strings.Join(sets, "")
+	buf := make([]byte, length)
+	for i := range buf {
+		set := all
+		if i < len(sets) {
+			set = sets[i]
+		}
+		c, err := randomIndex(len(set))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = set[c]
+	}
+	for i := len(buf) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf), nil
+}
+
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("unable to generate password: %v", err)
+	}
+	return int(i.Int64()), nil
+}
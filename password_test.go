@@ -0,0 +1,89 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	var tests = []struct {
+		policy PasswordPolicy
+		length int
+	}{
+		{PasswordPolicy{}, DefaultPasswordLength},
+		{PasswordPolicy{MinLength: 24}, 24},
+		{PasswordPolicy{MaxLength: 8}, 8},
+		{PasswordPolicy{MinLength: 8, MaxLength: 12, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSpecial: true}, 12},
+		{PasswordPolicy{RequireSpecial: true, SpecialChars: "$"}, DefaultPasswordLength},
+		{PasswordPolicy{MaxLength: 4, RequireSpecial: true}, 4},
+	}
+	for i, test := range tests {
+		for n := 0; n < 50; n++ {
+			password, err := GeneratePassword(test.policy)
+			if err != nil {
+				t.Fatalf("Test %v Failed: %v", i, err)
+			}
+			if len(password) != test.length {
+				t.Errorf("Test %v Failed: Expected length %d but got %d", i, test.length, len(password))
+			}
+			if err := test.policy.Check(password); err != nil {
+				t.Errorf("Test %v Failed: Generated password fails its policy: %v", i, err)
+			}
+			if test.policy.SpecialChars == "$" && !strings.Contains(password, "$") {
+				t.Errorf("Test %v Failed: Expected the policy's special character in %q", i, password)
+			}
+		}
+	}
+
+	if _, err := GeneratePassword(PasswordPolicy{MaxLength: 3, RequireSpecial: true}); err == nil {
+		t.Errorf("Expected an error for a policy no password can satisfy")
+	}
+	a, _ := GeneratePassword(PasswordPolicy{})
+	b, _ := GeneratePassword(PasswordPolicy{})
+	if a == b {
+		t.Errorf("Expected different passwords but got %q twice", a)
+	}
+}
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, MaxLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSpecial: true}
+	var tests = []struct {
+		password string
+		ok       bool
+	}{
+		{"Abcdef1!", true},
+		{"Abc1!", false},
+		{"Abcdefghij1!", false},
+		{"abcdefg1!", false},
+		{"ABCDEFG1!", false},
+		{"Abcdefgh!", false},
+		{"Abcdefgh1", false},
+	}
+	for i, test := range tests {
+		if err := policy.Check(test.password); (err == nil) != test.ok {
+			t.Errorf("Test %v Failed: Expected ok=%v for %q but got %v", i, test.ok, test.password, err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// The Redfish account collection, relative to a BMC's base URL.
+const accountsPath = "/redfish/v1/AccountService/Accounts"
+
+// StatusError is returned when a BMC answers a request with a status other
+// than 2xx.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Status)
+}
+
+// AccountManager maintains BMC accounts through the Redfish AccountService,
+// authenticating with the credentials in a CompCredStore and keeping the
+// store in step with the BMC.
+type AccountManager struct {
+	Store *cc.CompCredStore
+
+	// The client requests are made with. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// How long password policies are cached. Defaults to
+	// DefaultPolicyTTL.
+	PolicyTTL time.Duration
+
+	// The key space ephemeral accounts are recorded in. Defaults to the
+	// store's key space with "-ephemeral" appended.
+	EphemeralPath string
+
+	policies policyCache
+	now      func() time.Time
+}
+
+// Create a new AccountManager.
+func NewAccountManager(ccs *cc.CompCredStore) *AccountManager {
+	return &AccountManager{Store: ccs}
+}
+
+func (am *AccountManager) clock() time.Time {
+	if am.now != nil {
+		return am.now()
+	}
+	return time.Now()
+}
+
+func (am *AccountManager) client() *http.Client {
+	if am.Client != nil {
+		return am.Client
+	}
+	return http.DefaultClient
+}
+
+// Get the stored credentials for an xname, which must include a URL.
+func (am *AccountManager) credentials(xname string) (cc.CompCredentials, error) {
+	cred, err := am.Store.GetCompCred(xname)
+	if err != nil {
+		return cred, fmt.Errorf("unable to get credentials for %s: %v", xname, err)
+	}
+	if cred.Xname == "" {
+		return cred, fmt.Errorf("no credentials stored for %s", xname)
+	}
+	if CredBaseURL(cred) == "" {
+		return cred, fmt.Errorf("no URL stored for %s", xname)
+	}
+	return cred, nil
+}
+
+// Make a request with basic authentication, sending body and decoding the
+// response into out when they are not nil.
+func (am *AccountManager) do(ctx context.Context, method, uri string, cred cc.CompCredentials, body, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, uri, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(cred.Username, cred.Password)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := am.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, resp.Body)
+		return resp.Header, &StatusError{Method: method, URL: uri, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.Header, fmt.Errorf("unable to decode response from %s: %v", uri, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+var errNoAccount = errors.New("no account")
+
+type odataLink struct {
+	ID string `json:"@odata.id"`
+}
+
+type account struct {
+	ID       string `json:"@odata.id"`
+	UserName string
+	RoleId   string
+	Enabled  bool
+}
+
+// Find the URI of the account with a username, authenticating with cred.
+func (am *AccountManager) findAccount(ctx context.Context, cred cc.CompCredentials, username string) (string, error) {
+	base := CredBaseURL(cred)
+	var coll struct {
+		Members []odataLink
+	}
+	if _, err := am.do(ctx, http.MethodGet, base+accountsPath, cred, nil, &coll); err != nil {
+		return "", err
+	}
+	for _, m := range coll.Members {
+		uri := resolveRef(base, m.ID)
+		var acct account
+		if _, err := am.do(ctx, http.MethodGet, uri, cred, nil, &acct); err != nil {
+			return "", err
+		}
+		if acct.UserName == username {
+			return uri, nil
+		}
+	}
+	return "", fmt.Errorf("%w %s on %s", errNoAccount, username, cred.Xname)
+}
+
+// Resolve a reference, such as an @odata.id, against a BMC's base URL.
+func resolveRef(base, ref string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := u.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return r.String()
+}
+
+// Change the password of the stored account on an xname's BMC and then in
+// the store. The BMC is updated through the AccountService, a login with the
+// new password is checked, and only then is the new password stored. If the
+// check or the store fails, the BMC is set back to the old password so the
+// two do not drift; an error that says the rollback failed means they have.
+func (am *AccountManager) SetPassword(ctx context.Context, xname, password string) error {
+	old, err := am.credentials(xname)
+	if err != nil {
+		return err
+	}
+	updated := old
+	updated.Password = password
+	// A reused password is refused before the BMC is changed.
+	if err := am.Store.CheckPasswordReuse(updated); err != nil {
+		return err
+	}
+	uri, err := am.findAccount(ctx, old, old.Username)
+	if err != nil {
+		return fmt.Errorf("unable to find account for %s: %w", xname, err)
+	}
+
+	if _, err := am.do(ctx, http.MethodPatch, uri, old, map[string]string{"Password": password}, nil); err != nil {
+		return fmt.Errorf("unable to change password on %s: %w", xname, err)
+	}
+
+	if _, err := am.do(ctx, http.MethodGet, uri, updated, nil, nil); err != nil {
+		return am.rollback(ctx, uri, old, updated,
+			fmt.Errorf("unable to log in to %s with the new password: %w", xname, err))
+	}
+
+	if err := am.Store.StoreCompCred(updated); err != nil {
+		return am.rollback(ctx, uri, old, updated,
+			fmt.Errorf("unable to store new password for %s: %w", xname, err))
+	}
+	return nil
+}
+
+// Set a BMC account back to its old password after a failed change. The BMC
+// may or may not have applied the change, so the old password is restored
+// authenticating with the new one, and failing that the old one.
+func (am *AccountManager) rollback(ctx context.Context, uri string, old, updated cc.CompCredentials, cause error) error {
+	restore := map[string]string{"Password": old.Password}
+	_, err := am.do(ctx, http.MethodPatch, uri, updated, restore, nil)
+	if err != nil {
+		_, err = am.do(ctx, http.MethodPatch, uri, old, restore, nil)
+	}
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("unable to restore the old password on %s, the BMC and the store differ: %w", old.Xname, err))
+	}
+	return cause
+}
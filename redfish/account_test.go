@@ -0,0 +1,225 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+	"github.com/Cray-HPE/hms-compcredentials/redfishtest"
+)
+
+// failingStorage fails every Store while fail is set, and every Delete
+// while failDelete is.
+type failingStorage struct {
+	*conformance.MemoryStorage
+	mu         sync.Mutex
+	fail       bool
+	failDelete bool
+}
+
+func (fs *failingStorage) setFail(fail bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.fail = fail
+}
+
+func (fs *failingStorage) Store(key string, value interface{}) error {
+	fs.mu.Lock()
+	fail := fs.fail
+	fs.mu.Unlock()
+	if fail {
+		return errors.New("storage unavailable")
+	}
+	return fs.MemoryStorage.Store(key, value)
+}
+
+func (fs *failingStorage) setFailDelete(fail bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.failDelete = fail
+}
+
+func (fs *failingStorage) Delete(key string) error {
+	fs.mu.Lock()
+	fail := fs.failDelete
+	fs.mu.Unlock()
+	if fail {
+		return errors.New("storage unavailable")
+	}
+	return fs.MemoryStorage.Delete(key)
+}
+
+func setupAccounts(t *testing.T) (*redfishtest.Server, *failingStorage, *AccountManager) {
+	bmc := redfishtest.NewServer()
+	t.Cleanup(bmc.Close)
+	bmc.AddAccount("admin", "adminpass", "Administrator")
+	bmc.AddAccount("root", "initial0", "Administrator")
+
+	ss := &failingStorage{MemoryStorage: conformance.NewMemoryStorage()}
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	err := ccs.StoreCompCred(cc.CompCredentials{
+		Xname:    "x0c0s0b0",
+		URL:      bmc.URL + "/redfish/v1",
+		Username: "root",
+		Password: "initial0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bmc, ss, NewAccountManager(ccs)
+}
+
+// Check the password on the BMC and in the store.
+func checkPasswords(t *testing.T, bmc *redfishtest.Server, am *AccountManager, expected string) {
+	t.Helper()
+	acct, _ := bmc.Account("root")
+	if acct.Password != expected {
+		t.Errorf("Expected BMC password %q but got %q", expected, acct.Password)
+	}
+	cred, err := am.Store.GetCompCred("x0c0s0b0")
+	if err != nil || cred.Password != expected {
+		t.Errorf("Expected stored password %q but got %q, %v", expected, cred.Password, err)
+	}
+}
+
+// Answer the first PATCH with success without applying it, as a BMC that
+// silently ignores the change would.
+func ignoreFirstPatch(bmc *redfishtest.Server) {
+	var once sync.Once
+	bmc.Intercept(func(w http.ResponseWriter, r *http.Request) bool {
+		ignored := false
+		if r.Method == http.MethodPatch {
+			once.Do(func() {
+				w.WriteHeader(http.StatusNoContent)
+				ignored = true
+			})
+		}
+		return ignored
+	})
+}
+
+func TestSetPassword(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+
+	if err := am.SetPassword(context.Background(), "x0c0s0b0", "rotated01"); err != nil {
+		t.Fatalf("SetPassword failed: %v", err)
+	}
+	checkPasswords(t, bmc, am, "rotated01")
+
+	if err := am.SetPassword(context.Background(), "x9c9s9b9", "rotated01"); err == nil {
+		t.Errorf("Expected an error for an xname with no credentials")
+	}
+}
+
+func TestSetPasswordRejected(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+
+	err := am.SetPassword(context.Background(), "x0c0s0b0", "short")
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a 400 StatusError but got %v", err)
+	}
+	checkPasswords(t, bmc, am, "initial0")
+}
+
+func TestSetPasswordReused(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	am.Store.PasswordReuse = &cc.ReusePolicy{Remember: 3, Key: bytes.Repeat([]byte("k"), cc.MinReuseKeyLength)}
+
+	if err := am.SetPassword(context.Background(), "x0c0s0b0", "rotated01"); err != nil {
+		t.Fatalf("SetPassword failed: %v", err)
+	}
+	patches := 0
+	bmc.Intercept(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodPatch {
+			patches++
+		}
+		return false
+	})
+	if err := am.SetPassword(context.Background(), "x0c0s0b0", "initial0"); !errors.Is(err, cc.ErrPasswordReused) {
+		t.Errorf("Expected the old password to be refused but got %v", err)
+	}
+	if patches != 0 {
+		t.Errorf("Expected the BMC not to be changed but got %d PATCH requests", patches)
+	}
+	checkPasswords(t, bmc, am, "rotated01")
+}
+
+func TestSetPasswordNotApplied(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	ignoreFirstPatch(bmc)
+
+	err := am.SetPassword(context.Background(), "x0c0s0b0", "rotated01")
+	if err == nil || !strings.Contains(err.Error(), "log in") {
+		t.Errorf("Expected a failed login with the new password but got %v", err)
+	}
+	checkPasswords(t, bmc, am, "initial0")
+}
+
+func TestSetPasswordStoreFails(t *testing.T) {
+	bmc, ss, am := setupAccounts(t)
+	ss.setFail(true)
+
+	err := am.SetPassword(context.Background(), "x0c0s0b0", "rotated01")
+	if err == nil || !strings.Contains(err.Error(), "storage unavailable") {
+		t.Errorf("Expected the store error but got %v", err)
+	}
+	if strings.Contains(err.Error(), "differ") {
+		t.Errorf("Expected the rollback to succeed but got %v", err)
+	}
+	checkPasswords(t, bmc, am, "initial0")
+}
+
+func TestSetPasswordRollbackFails(t *testing.T) {
+	bmc, ss, am := setupAccounts(t)
+	ss.setFail(true)
+	patches := 0
+	bmc.Intercept(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodPatch {
+			return false
+		}
+		patches++
+		if patches == 1 {
+			return false
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return true
+	})
+
+	err := am.SetPassword(context.Background(), "x0c0s0b0", "rotated01")
+	if err == nil || !strings.Contains(err.Error(), "differ") {
+		t.Errorf("Expected an error saying the BMC and store differ but got %v", err)
+	}
+	acct, _ := bmc.Account("root")
+	if acct.Password != "rotated01" {
+		t.Errorf("Expected the BMC to keep the new password but got %q", acct.Password)
+	}
+}
@@ -0,0 +1,366 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+const (
+	// The lifetime of ephemeral accounts when none is requested.
+	DefaultEphemeralTTL = time.Hour
+
+	// The role ephemeral accounts get when none is requested.
+	DefaultEphemeralRole = "ReadOnly"
+
+	// How often RunReaper looks for expired accounts when no interval is
+	// given.
+	DefaultReapInterval = 5 * time.Minute
+)
+
+// EphemeralRequest describes a temporary BMC account to create.
+type EphemeralRequest struct {
+	// What the account is for, such as a job ID or support case.
+	Job string
+
+	// The account's username. Defaults to a random name starting "tmp".
+	Username string
+
+	// The account's Redfish role. Defaults to DefaultEphemeralRole.
+	RoleId string
+
+	// How long the account lives. Defaults to DefaultEphemeralTTL.
+	TTL time.Duration
+}
+
+// EphemeralAccount is a temporary BMC account, created with the stored
+// administrator credentials for its xname and deleted once it expires.
+type EphemeralAccount struct {
+	Xname    string    `json:"xname"`
+	URL      string    `json:"url"`
+	Username string    `json:"username"`
+	Password string    `json:"password"`
+	RoleId   string    `json:"role_id"`
+	Job      string    `json:"job,omitempty"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+
+	// The account's resource on the BMC, once it has been created.
+	URI string `json:"uri,omitempty"`
+}
+
+// Don't print the password by accident.
+func (ea EphemeralAccount) String() string {
+	return fmt.Sprintf("Xname: %s, Username: %s, Password: %s, Role: %s, Job: %s, Expires: %s",
+		ea.Xname, ea.Username, cc.RedactedValue, ea.RoleId, ea.Job, ea.Expires.Format(time.RFC3339))
+}
+
+// Check whether an account has expired.
+func (ea EphemeralAccount) Expired(now time.Time) bool {
+	return !now.Before(ea.Expires)
+}
+
+// The form ephemeral accounts are stored in. Times are RFC 3339 strings so
+// every SecureStorage can hold them.
+type ephemeralRecord struct {
+	Xname    string
+	URL      string
+	Username string
+	Password string
+	RoleId   string
+	Job      string
+	Created  string
+	Expires  string
+	URI      string
+
+	// Set by CreateEphemeral, which checks the username is not already on
+	// the BMC before recording it. Only then can an account found by name,
+	// when no URI was recorded, be the one this record is for.
+	NameChecked bool
+}
+
+func (r ephemeralRecord) account() EphemeralAccount {
+	created, _ := time.Parse(time.RFC3339, r.Created)
+	expires, _ := time.Parse(time.RFC3339, r.Expires)
+	return EphemeralAccount{
+		Xname: r.Xname, URL: r.URL, Username: r.Username, Password: r.Password,
+		RoleId: r.RoleId, Job: r.Job, Created: created, Expires: expires, URI: r.URI,
+	}
+}
+
+func newEphemeralRecord(ea EphemeralAccount) ephemeralRecord {
+	return ephemeralRecord{
+		Xname: ea.Xname, URL: ea.URL, Username: ea.Username, Password: ea.Password,
+		RoleId: ea.RoleId, Job: ea.Job, URI: ea.URI,
+		Created:     ea.Created.UTC().Format(time.RFC3339),
+		Expires:     ea.Expires.UTC().Format(time.RFC3339),
+		NameChecked: true,
+	}
+}
+
+// Get the key space ephemeral accounts are recorded in.
+func (am *AccountManager) ephemeralPath() string {
+	if am.EphemeralPath != "" {
+		return am.EphemeralPath
+	}
+	return am.Store.CCPath + "-ephemeral"
+}
+
+func (am *AccountManager) ephemeralKey(xname, username string) string {
+	return am.ephemeralPath() + "/" + xname + "/" + username
+}
+
+func randomUsername() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "tmp" + hex.EncodeToString(buf), nil
+}
+
+// Create a temporary account on an xname's BMC, using its stored
+// administrator credentials, and record it with its expiry. The account is
+// recorded before it is created on the BMC, so the reaper can find it even
+// if creation is interrupted. Usernames already on the BMC or recorded, and
+// the administrator's, are refused, so the reaper never deletes an account
+// it did not create.
+func (am *AccountManager) CreateEphemeral(ctx context.Context, xname string, req EphemeralRequest) (EphemeralAccount, error) {
+	admin, err := am.credentials(xname)
+	if err != nil {
+		return EphemeralAccount{}, err
+	}
+
+	ea := EphemeralAccount{
+		Xname:    xname,
+		URL:      admin.URL,
+		Username: req.Username,
+		RoleId:   req.RoleId,
+		Job:      req.Job,
+		Created:  am.clock().UTC().Truncate(time.Second),
+	}
+	if ea.Username == "" {
+		if ea.Username, err = randomUsername(); err != nil {
+			return EphemeralAccount{}, err
+		}
+	}
+	if ea.Username == admin.Username {
+		return EphemeralAccount{}, fmt.Errorf("%s is the administrator account of %s", ea.Username, xname)
+	}
+	if ea.RoleId == "" {
+		ea.RoleId = DefaultEphemeralRole
+	}
+	ttl := req.TTL
+	if ttl <= 0 {
+		ttl = DefaultEphemeralTTL
+	}
+	ea.Expires = ea.Created.Add(ttl)
+	if ea.Password, err = am.GeneratePassword(ctx, xname); err != nil {
+		return EphemeralAccount{}, err
+	}
+
+	key := am.ephemeralKey(xname, ea.Username)
+	existing, err := am.lookupEphemeral(xname, ea.Username)
+	if err != nil {
+		return EphemeralAccount{}, err
+	}
+	if existing.Username != "" {
+		return EphemeralAccount{}, fmt.Errorf("ephemeral account %s is already recorded for %s", ea.Username, xname)
+	}
+	if _, err := am.findAccount(ctx, admin, ea.Username); err == nil {
+		return EphemeralAccount{}, fmt.Errorf("account %s already exists on %s", ea.Username, xname)
+	} else if !errors.Is(err, errNoAccount) {
+		return EphemeralAccount{}, fmt.Errorf("unable to check for account %s on %s: %w", ea.Username, xname, err)
+	}
+	if err := am.Store.SS.Store(key, newEphemeralRecord(ea)); err != nil {
+		return EphemeralAccount{}, fmt.Errorf("unable to record ephemeral account for %s: %v", xname, err)
+	}
+
+	var created odataLink
+	body := map[string]interface{}{
+		"UserName": ea.Username,
+		"Password": ea.Password,
+		"RoleId":   ea.RoleId,
+		"Enabled":  true,
+	}
+	header, err := am.do(ctx, http.MethodPost, CredBaseURL(admin)+accountsPath, admin, body, &created)
+	if err != nil {
+		if derr := am.Store.SS.Delete(key); derr != nil {
+			return EphemeralAccount{}, fmt.Errorf("unable to create ephemeral account on %s: %w, or to remove its record: %v", xname, err, derr)
+		}
+		return EphemeralAccount{}, fmt.Errorf("unable to create ephemeral account on %s: %w", xname, err)
+	}
+	ea.URI = created.ID
+	if location := header.Get("Location"); location != "" {
+		ea.URI = location
+	}
+	if ea.URI != "" {
+		ea.URI = resolveRef(CredBaseURL(admin), ea.URI)
+	}
+	if err := am.Store.SS.Store(key, newEphemeralRecord(ea)); err != nil {
+		// The first record is enough for the reaper to find the account.
+		log.WithError(err).WithField("xname", xname).Warn("Unable to record ephemeral account URI")
+	}
+	return ea, nil
+}
+
+// Get an ephemeral account. The account is zero if it is not recorded.
+func (am *AccountManager) GetEphemeral(xname, username string) (EphemeralAccount, error) {
+	rec, err := am.lookupEphemeral(xname, username)
+	if err != nil || rec.Username == "" {
+		return EphemeralAccount{}, err
+	}
+	return rec.account(), nil
+}
+
+func (am *AccountManager) lookupEphemeral(xname, username string) (ephemeralRecord, error) {
+	var rec ephemeralRecord
+	err := am.Store.SS.Lookup(am.ephemeralKey(xname, username), &rec)
+	return rec, err
+}
+
+// List the recorded ephemeral accounts.
+func (am *AccountManager) ListEphemeral() ([]EphemeralAccount, error) {
+	xnames, err := cc.LookupKeys(am.Store.SS, am.ephemeralPath())
+	if err != nil {
+		return nil, err
+	}
+	var accounts []EphemeralAccount
+	for _, xname := range xnames {
+		xname = strings.TrimSuffix(xname, "/")
+		usernames, err := cc.LookupKeys(am.Store.SS, am.ephemeralPath()+"/"+xname)
+		if err != nil {
+			return nil, err
+		}
+		for _, username := range usernames {
+			ea, err := am.GetEphemeral(xname, username)
+			if err != nil {
+				return nil, err
+			}
+			if ea.Username != "" {
+				accounts = append(accounts, ea)
+			}
+		}
+	}
+	return accounts, nil
+}
+
+// Delete an ephemeral account from the BMC, using the stored administrator
+// credentials, and then its record. An account already gone from the BMC is
+// not an error. An account whose URI was not recorded is only looked up by
+// name if CreateEphemeral made the record.
+func (am *AccountManager) DeleteEphemeral(ctx context.Context, xname, username string) error {
+	ea, err := am.lookupEphemeral(xname, username)
+	if err != nil {
+		return err
+	}
+	if ea.Username == "" {
+		return fmt.Errorf("no ephemeral account %s recorded for %s", username, xname)
+	}
+	admin, err := am.credentials(xname)
+	if err != nil {
+		return err
+	}
+
+	uri := ea.URI
+	if uri == "" && !ea.NameChecked {
+		log.WithFields(log.Fields{"xname": xname, "username": username}).
+			Warn("Not deleting an ephemeral account with no recorded URI by name, as it may not be one")
+	} else if uri == "" {
+		// Creation may have been interrupted before the URI was recorded,
+		// or before the account was created at all.
+		uri, err = am.findAccount(ctx, admin, username)
+		if err != nil && !errors.Is(err, errNoAccount) {
+			return fmt.Errorf("unable to find ephemeral account %s on %s: %w", username, xname, err)
+		}
+	}
+	if uri != "" {
+		_, err := am.do(ctx, http.MethodDelete, uri, admin, nil, nil)
+		var se *StatusError
+		if err != nil && !(errors.As(err, &se) && se.StatusCode == http.StatusNotFound) {
+			return fmt.Errorf("unable to delete ephemeral account %s on %s: %w", username, xname, err)
+		}
+	}
+	return am.Store.SS.Delete(am.ephemeralKey(xname, username))
+}
+
+// Delete every expired ephemeral account from its BMC and the store,
+// returning the accounts deleted. Accounts that cannot be deleted are kept
+// for the next attempt and their errors returned together.
+func (am *AccountManager) Reap(ctx context.Context) ([]EphemeralAccount, error) {
+	accounts, err := am.ListEphemeral()
+	if err != nil {
+		return nil, err
+	}
+	now := am.clock()
+	var (
+		reaped []EphemeralAccount
+		errs   []error
+	)
+	for _, ea := range accounts {
+		if !ea.Expired(now) {
+			continue
+		}
+		if err := am.DeleteEphemeral(ctx, ea.Xname, ea.Username); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reaped = append(reaped, ea)
+	}
+	return reaped, errors.Join(errs...)
+}
+
+// Reap expired ephemeral accounts every interval until the context is done.
+func (am *AccountManager) RunReaper(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultReapInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		reaped, err := am.Reap(ctx)
+		for _, ea := range reaped {
+			log.WithFields(log.Fields{"xname": ea.Xname, "username": ea.Username, "job": ea.Job}).
+				Info("Deleted expired ephemeral account")
+		}
+		if err != nil {
+			log.WithError(err).Error("Reaping ephemeral accounts failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/vaulttest"
+)
+
+// Check whether an account can log in to the BMC with basic authentication.
+func canLogIn(t *testing.T, am *AccountManager, url, username, password string) bool {
+	t.Helper()
+	cred := cc.CompCredentials{URL: url, Username: username, Password: password}
+	_, err := am.do(context.Background(), http.MethodGet, url+"/redfish/v1/Systems", cred, nil, nil)
+	return err == nil
+}
+
+func TestEphemeral(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	ctx := context.Background()
+	now := time.Now()
+	am.now = func() time.Time { return now }
+
+	ea, err := am.CreateEphemeral(ctx, "x0c0s0b0", EphemeralRequest{Job: "case-1234", TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("CreateEphemeral failed: %v", err)
+	}
+	if !strings.HasPrefix(ea.Username, "tmp") || ea.RoleId != DefaultEphemeralRole || ea.URI == "" {
+		t.Errorf("Unexpected account %+v", ea)
+	}
+	if !canLogIn(t, am, bmc.URL, ea.Username, ea.Password) {
+		t.Errorf("Expected the ephemeral account to log in")
+	}
+	if strings.Contains(ea.String(), ea.Password) {
+		t.Errorf("Expected String to redact the password but got %s", ea)
+	}
+
+	long, err := am.CreateEphemeral(ctx, "x0c0s0b0", EphemeralRequest{Username: "vendor", RoleId: "Operator"})
+	if err != nil {
+		t.Fatalf("CreateEphemeral failed: %v", err)
+	}
+	if acct, ok := bmc.Account("vendor"); !ok || acct.RoleId != "Operator" {
+		t.Errorf("Expected an Operator account named vendor but got %+v", acct)
+	}
+
+	got, err := am.GetEphemeral("x0c0s0b0", ea.Username)
+	if err != nil || got != ea {
+		t.Errorf("Expected recorded account %+v but got %+v, %v", ea, got, err)
+	}
+	accounts, err := am.ListEphemeral()
+	if err != nil || len(accounts) != 2 {
+		t.Errorf("Expected 2 recorded accounts but got %v, %v", accounts, err)
+	}
+
+	// Nothing has expired yet.
+	if reaped, err := am.Reap(ctx); err != nil || len(reaped) != 0 {
+		t.Errorf("Expected nothing reaped but got %v, %v", reaped, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	reaped, err := am.Reap(ctx)
+	if err != nil || len(reaped) != 1 || reaped[0].Username != ea.Username {
+		t.Fatalf("Expected %s to be reaped but got %v, %v", ea.Username, reaped, err)
+	}
+	if _, ok := bmc.Account(ea.Username); ok {
+		t.Errorf("Expected the expired account to be deleted from the BMC")
+	}
+	if got, _ := am.GetEphemeral("x0c0s0b0", ea.Username); got.Username != "" {
+		t.Errorf("Expected the expired account's record to be deleted")
+	}
+
+	if err := am.DeleteEphemeral(ctx, "x0c0s0b0", long.Username); err != nil {
+		t.Errorf("DeleteEphemeral failed: %v", err)
+	}
+	if _, ok := bmc.Account("vendor"); ok {
+		t.Errorf("Expected the account to be deleted from the BMC")
+	}
+	if err := am.DeleteEphemeral(ctx, "x0c0s0b0", long.Username); err == nil {
+		t.Errorf("Expected an error deleting an account that is not recorded")
+	}
+}
+
+func TestEphemeralCreateFails(t *testing.T) {
+	bmc, ss, am := setupAccounts(t)
+	bmc.Intercept(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/Accounts") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return true
+		}
+		return false
+	})
+
+	if _, err := am.CreateEphemeral(context.Background(), "x0c0s0b0", EphemeralRequest{}); err == nil {
+		t.Errorf("Expected CreateEphemeral to fail")
+	}
+	if accounts, err := am.ListEphemeral(); err != nil || len(accounts) != 0 {
+		t.Errorf("Expected no recorded accounts but got %v, %v", accounts, err)
+	}
+
+	// A record that cannot be removed is reported.
+	ss.setFailDelete(true)
+	_, err := am.CreateEphemeral(context.Background(), "x0c0s0b0", EphemeralRequest{})
+	if err == nil || !strings.Contains(err.Error(), "remove its record") {
+		t.Errorf("Expected an error for the record left behind but got %v", err)
+	}
+}
+
+func TestEphemeralRefused(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	ctx := context.Background()
+	live, err := am.CreateEphemeral(ctx, "x0c0s0b0", EphemeralRequest{Username: "tmplive"})
+	if err != nil {
+		t.Fatalf("CreateEphemeral failed: %v", err)
+	}
+
+	var tests = []struct {
+		username string
+		err      string
+	}{
+		{"root", "administrator account"},
+		{"admin", "already exists"},
+		{"tmplive", "already recorded"},
+	}
+	for i, test := range tests {
+		_, err := am.CreateEphemeral(ctx, "x0c0s0b0", EphemeralRequest{Username: test.username})
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Test %v Failed: Expected an error containing %q but got %v", i, test.err, err)
+		}
+	}
+	if got, _ := am.GetEphemeral("x0c0s0b0", "tmplive"); got != live {
+		t.Errorf("Expected the live account's record to be kept but got %+v", got)
+	}
+	if acct, ok := bmc.Account("admin"); !ok || acct.RoleId != "Administrator" {
+		t.Errorf("Expected the admin account to be left alone but got %+v", acct)
+	}
+}
+
+func TestEphemeralInterrupted(t *testing.T) {
+	bmc, ss, am := setupAccounts(t)
+	ctx := context.Background()
+
+	// Records left by creations interrupted before and after the BMC
+	// account was made, but before its URI was recorded, and one
+	// CreateEphemeral did not make.
+	bmc.AddAccount("tmpcreated", "password01", "ReadOnly")
+	bmc.AddAccount("vendor", "password02", "Operator")
+	expired := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	records := []ephemeralRecord{
+		{Xname: "x0c0s0b0", Username: "tmpcreated", Expires: expired, NameChecked: true},
+		{Xname: "x0c0s0b0", Username: "tmpnever", Expires: expired, NameChecked: true},
+		{Xname: "x0c0s0b0", Username: "vendor", Expires: expired},
+	}
+	for _, rec := range records {
+		if err := ss.Store(am.ephemeralKey(rec.Xname, rec.Username), rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reaped, err := am.Reap(ctx)
+	if err != nil || len(reaped) != 3 {
+		t.Errorf("Expected every account reaped but got %v, %v", reaped, err)
+	}
+	if _, ok := bmc.Account("tmpcreated"); ok {
+		t.Errorf("Expected the account to be found and deleted from the BMC")
+	}
+	if _, ok := bmc.Account("vendor"); !ok {
+		t.Errorf("Expected an account CreateEphemeral did not record to be left on the BMC")
+	}
+}
+
+func TestEphemeralEmptyKeySpace(t *testing.T) {
+	// The VaultAdapter panics listing a path with no keys.
+	vault := vaulttest.NewServer()
+	t.Cleanup(vault.Close)
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, vault.NewVaultAdapter(t, "secret"))
+	am := NewAccountManager(ccs)
+
+	if reaped, err := am.Reap(context.Background()); err != nil || len(reaped) != 0 {
+		t.Errorf("Expected nothing reaped from an empty key space but got %v, %v", reaped, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := am.RunReaper(ctx, time.Millisecond); err != nil {
+		t.Errorf("Expected RunReaper to stop cleanly but got %v", err)
+	}
+}
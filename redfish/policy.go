@@ -0,0 +1,134 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// How long AccountManager caches password policies when PolicyTTL is not
+// set.
+const DefaultPolicyTTL = time.Hour
+
+// The Redfish AccountService, relative to a BMC's base URL.
+const accountServicePath = "/redfish/v1/AccountService"
+
+// OEMPolicyParsers refine a password policy from the vendor properties under
+// an AccountService's Oem object, keyed by vendor. Add to it to support
+// another vendor.
+var OEMPolicyParsers = map[string]func(oem json.RawMessage, policy *cc.PasswordPolicy){
+	"Hpe": parseHpePolicy,
+}
+
+// HPE iLO reports its own minimum length and whether complexity is enforced,
+// which requires characters from at least three of the four classes.
+func parseHpePolicy(oem json.RawMessage, policy *cc.PasswordPolicy) {
+	var hpe struct {
+		MinPasswordLength         int
+		EnforcePasswordComplexity bool
+	}
+	if json.Unmarshal(oem, &hpe) != nil {
+		return
+	}
+	if hpe.MinPasswordLength > policy.MinLength {
+		policy.MinLength = hpe.MinPasswordLength
+	}
+	if hpe.EnforcePasswordComplexity {
+		policy.RequireUpper = true
+		policy.RequireLower = true
+		policy.RequireDigit = true
+		policy.RequireSpecial = true
+	}
+}
+
+type policyEntry struct {
+	policy  cc.PasswordPolicy
+	expires time.Time
+}
+
+type policyCache struct {
+	mu      sync.Mutex
+	entries map[string]policyEntry
+}
+
+// Get the password policy of an xname's BMC from its AccountService and
+// vendor OEM properties, reading it with the stored credentials. Policies
+// are cached for PolicyTTL.
+func (am *AccountManager) PasswordPolicy(ctx context.Context, xname string) (cc.PasswordPolicy, error) {
+	ttl := am.PolicyTTL
+	if ttl == 0 {
+		ttl = DefaultPolicyTTL
+	}
+
+	am.policies.mu.Lock()
+	entry, ok := am.policies.entries[xname]
+	am.policies.mu.Unlock()
+	if ok && am.clock().Before(entry.expires) {
+		return entry.policy, nil
+	}
+
+	cred, err := am.credentials(xname)
+	if err != nil {
+		return cc.PasswordPolicy{}, err
+	}
+	var svc struct {
+		MinPasswordLength int
+		MaxPasswordLength int
+		Oem               map[string]json.RawMessage
+	}
+	if _, err := am.do(ctx, http.MethodGet, CredBaseURL(cred)+accountServicePath, cred, nil, &svc); err != nil {
+		return cc.PasswordPolicy{}, err
+	}
+	policy := cc.PasswordPolicy{
+		MinLength: svc.MinPasswordLength,
+		MaxLength: svc.MaxPasswordLength,
+	}
+	for vendor, oem := range svc.Oem {
+		if parse, ok := OEMPolicyParsers[vendor]; ok {
+			parse(oem, &policy)
+		}
+	}
+
+	am.policies.mu.Lock()
+	if am.policies.entries == nil {
+		am.policies.entries = make(map[string]policyEntry)
+	}
+	am.policies.entries[xname] = policyEntry{policy: policy, expires: am.clock().Add(ttl)}
+	am.policies.mu.Unlock()
+	return policy, nil
+}
+
+// Generate a password that an xname's BMC will accept.
+func (am *AccountManager) GeneratePassword(ctx context.Context, xname string) (string, error) {
+	policy, err := am.PasswordPolicy(ctx, xname)
+	if err != nil {
+		return "", err
+	}
+	return cc.GeneratePassword(policy)
+}
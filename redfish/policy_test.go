@@ -0,0 +1,113 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	bmc.SetPasswordPolicy(12, 16)
+	ctx := context.Background()
+
+	policy, err := am.PasswordPolicy(ctx, "x0c0s0b0")
+	if err != nil {
+		t.Fatalf("PasswordPolicy failed: %v", err)
+	}
+	if policy != (cc.PasswordPolicy{MinLength: 12, MaxLength: 16}) {
+		t.Errorf("Unexpected policy %+v", policy)
+	}
+
+	password, err := am.GeneratePassword(ctx, "x0c0s0b0")
+	if err != nil {
+		t.Fatalf("GeneratePassword failed: %v", err)
+	}
+	if err := policy.Check(password); err != nil {
+		t.Errorf("Generated password fails the policy: %v", err)
+	}
+	if err := am.SetPassword(ctx, "x0c0s0b0", password); err != nil {
+		t.Errorf("The BMC rejected a generated password: %v", err)
+	}
+
+	if _, err := am.PasswordPolicy(ctx, "x9c9s9b9"); err == nil {
+		t.Errorf("Expected an error for an xname with no credentials")
+	}
+}
+
+func TestPasswordPolicyOEM(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	bmc.SetPasswordPolicy(8, 39)
+	bmc.SetAccountServiceOem(map[string]interface{}{
+		"Hpe": map[string]interface{}{
+			"MinPasswordLength":         14,
+			"EnforcePasswordComplexity": true,
+		},
+		"Contoso": map[string]interface{}{"MinPasswordLength": 30},
+	})
+
+	policy, err := am.PasswordPolicy(context.Background(), "x0c0s0b0")
+	if err != nil {
+		t.Fatalf("PasswordPolicy failed: %v", err)
+	}
+	expected := cc.PasswordPolicy{
+		MinLength:      14,
+		MaxLength:      39,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+	}
+	if policy != expected {
+		t.Errorf("Expected policy %+v but got %+v", expected, policy)
+	}
+}
+
+func TestPasswordPolicyCache(t *testing.T) {
+	bmc, _, am := setupAccounts(t)
+	ctx := context.Background()
+	now := time.Now()
+	am.now = func() time.Time { return now }
+	am.PolicyTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		if _, err := am.PasswordPolicy(ctx, "x0c0s0b0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := bmc.Requests("GET /redfish/v1/AccountService"); n != 1 {
+		t.Errorf("Expected the policy to be read once but it was read %d times", n)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := am.PasswordPolicy(ctx, "x0c0s0b0"); err != nil {
+		t.Fatal(err)
+	}
+	if n := bmc.Requests("GET /redfish/v1/AccountService"); n != 2 {
+		t.Errorf("Expected the expired policy to be read again, read %d times", n)
+	}
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package redfish provides building blocks for Redfish clients that take
+// their credentials from a CompCredStore.
+package redfish
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Resolver maps a request to the xname whose credentials authenticate it.
+type Resolver interface {
+	Resolve(req *http.Request) (string, error)
+}
+
+// ResolverFunc adapts a function to a Resolver.
+type ResolverFunc func(req *http.Request) (string, error)
+
+func (f ResolverFunc) Resolve(req *http.Request) (string, error) {
+	return f(req)
+}
+
+// HostResolver maps request hosts to xnames. A host is looked up first with
+// its port, as in "10.1.1.1:8443", and then without it.
+type HostResolver map[string]string
+
+func (hr HostResolver) Resolve(req *http.Request) (string, error) {
+	if xname, ok := hr[req.URL.Host]; ok {
+		return xname, nil
+	}
+	if xname, ok := hr[req.URL.Hostname()]; ok {
+		return xname, nil
+	}
+	return "", fmt.Errorf("no xname known for host %s", req.URL.Host)
+}
+
+// XnameHostResolver treats the request's hostname as the xname, for BMCs
+// addressed by their xname, such as https://x1000c0s1b0/redfish/v1.
+var XnameHostResolver = ResolverFunc(func(req *http.Request) (string, error) {
+	host := req.URL.Hostname()
+	if host == "" {
+		return "", fmt.Errorf("request has no host")
+	}
+	return host, nil
+})
+
+// Build a HostResolver from the URLs of every stored credential.
+func NewURLResolver(ccs *cc.CompCredStore) (HostResolver, error) {
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return nil, err
+	}
+
+	hr := make(HostResolver)
+	for xname, cred := range creds {
+		if host := CredHost(cred); host != "" {
+			hr[host] = xname
+		}
+	}
+	return hr, nil
+}
+
+// Get a credential's URL with its scheme. Stored URLs usually have none, e.g.
+// "10.4.0.8/redfish/v1/UpdateService", and BMCs are reached over https.
+func CredURL(cred cc.CompCredentials) string {
+	if cred.URL == "" || strings.Contains(cred.URL, "://") {
+		return cred.URL
+	}
+	return "https://" + cred.URL
+}
+
+// Get the scheme and host of a credential's URL, such as
+// "https://10.4.0.8", for building Redfish URLs on the same BMC.
+func CredBaseURL(cred cc.CompCredentials) string {
+	u, err := url.Parse(CredURL(cred))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// Get the host, with its port if any, from a credential's URL.
+func CredHost(cred cc.CompCredentials) string {
+	u, err := url.Parse(CredURL(cred))
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
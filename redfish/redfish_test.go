@@ -0,0 +1,123 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+func TestCredHost(t *testing.T) {
+	var tests = []struct {
+		url  string
+		host string
+	}{
+		{"10.4.0.8/redfish/v1/UpdateService", "10.4.0.8"},
+		{"x1000c0s1b0:8443/redfish/v1", "x1000c0s1b0:8443"},
+		{"http://127.0.0.1:1234/redfish/v1", "127.0.0.1:1234"},
+		{"", ""},
+	}
+	for i, test := range tests {
+		if host := CredHost(cc.CompCredentials{URL: test.url}); host != test.host {
+			t.Errorf("Test %v Failed: Expected host %q but got %q", i, test.host, host)
+		}
+	}
+}
+
+func TestCredURL(t *testing.T) {
+	var tests = []struct {
+		url      string
+		expected string
+	}{
+		{"10.4.0.8/redfish/v1", "https://10.4.0.8/redfish/v1"},
+		{"http://127.0.0.1:8080/redfish/v1", "http://127.0.0.1:8080/redfish/v1"},
+		{"", ""},
+	}
+	for i, test := range tests {
+		if got := CredURL(cc.CompCredentials{URL: test.url}); got != test.expected {
+			t.Errorf("Test %v Failed: Expected URL %q but got %q", i, test.expected, got)
+		}
+	}
+}
+
+func TestCredBaseURL(t *testing.T) {
+	var tests = []struct {
+		url      string
+		expected string
+	}{
+		{"10.4.0.8/redfish/v1", "https://10.4.0.8"},
+		{"http://127.0.0.1:8080/redfish/v1", "http://127.0.0.1:8080"},
+		{"https://x0c0s0b0", "https://x0c0s0b0"},
+		{"", ""},
+	}
+	for i, test := range tests {
+		got := CredBaseURL(cc.CompCredentials{URL: test.url})
+		if got != test.expected {
+			t.Errorf("Test %v Failed: Expected base URL %q but got %q", i, test.expected, got)
+		}
+	}
+}
+
+func TestResolvers(t *testing.T) {
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", URL: "10.1.1.1/redfish/v1"})
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s2b0", URL: "10.1.1.2:8443/redfish/v1"})
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s3b0"})
+
+	hr, err := NewURLResolver(ccs)
+	if err != nil {
+		t.Fatalf("NewURLResolver failed: %v", err)
+	}
+	expected := HostResolver{"10.1.1.1": "x1000c0s1b0", "10.1.1.2:8443": "x1000c0s2b0"}
+	if !reflect.DeepEqual(hr, expected) {
+		t.Errorf("Expected resolver %v but got %v", expected, hr)
+	}
+
+	var tests = []struct {
+		resolver Resolver
+		url      string
+		xname    string
+		respErr  bool
+	}{
+		{hr, "https://10.1.1.1/redfish/v1", "x1000c0s1b0", false},
+		{hr, "https://10.1.1.1:443/redfish/v1", "x1000c0s1b0", false},
+		{hr, "https://10.1.1.2:8443/redfish/v1", "x1000c0s2b0", false},
+		{hr, "https://10.1.1.9/redfish/v1", "", true},
+		{XnameHostResolver, "https://x1000c0s1b0/redfish/v1", "x1000c0s1b0", false},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.url, nil)
+		xname, err := test.resolver.Resolve(req)
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+		}
+		if xname != test.xname {
+			t.Errorf("Test %v Failed: Expected xname %q but got %q", i, test.xname, xname)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// How long SessionManager uses a session before replacing it when Lifetime
+// is not set. BMCs commonly time sessions out after 30 minutes idle.
+const DefaultSessionLifetime = 10 * time.Minute
+
+// The Redfish session collection, relative to a BMC's base URL.
+const sessionsPath = "/redfish/v1/SessionService/Sessions"
+
+type session struct {
+	mu       sync.Mutex
+	token    string
+	location string
+	expires  time.Time
+}
+
+// SessionManager logs in to BMCs with their stored credentials and caches
+// the resulting Redfish session tokens per xname. A session is replaced once
+// it is older than Lifetime, or when the BMC rejects its token. Close logs
+// out of every session.
+type SessionManager struct {
+	Store *cc.CompCredStore
+
+	// The client sessions are created and deleted with. It must not use
+	// the SessionManager's own Transport. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// How long a session is used before it is replaced. Defaults to
+	// DefaultSessionLifetime.
+	Lifetime time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	now      func() time.Time
+}
+
+// Create a new SessionManager.
+func NewSessionManager(ccs *cc.CompCredStore) *SessionManager {
+	return &SessionManager{Store: ccs}
+}
+
+func (sm *SessionManager) client() *http.Client {
+	if sm.Client != nil {
+		return sm.Client
+	}
+	return http.DefaultClient
+}
+
+func (sm *SessionManager) clock() time.Time {
+	if sm.now != nil {
+		return sm.now()
+	}
+	return time.Now()
+}
+
+func (sm *SessionManager) slot(xname string) *session {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.sessions == nil {
+		sm.sessions = make(map[string]*session)
+	}
+	s, ok := sm.sessions[xname]
+	if !ok {
+		s = &session{}
+		sm.sessions[xname] = s
+	}
+	return s
+}
+
+// Get a session token for an xname, logging in if there is no current
+// session.
+func (sm *SessionManager) Token(ctx context.Context, xname string) (string, error) {
+	return sm.token(ctx, xname, "")
+}
+
+// Get a session token for an xname. If the current token is stale, the
+// session is replaced; concurrent callers that saw the same stale token
+// share one new session.
+func (sm *SessionManager) token(ctx context.Context, xname, stale string) (string, error) {
+	s := sm.slot(xname)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.token != stale && sm.clock().Before(s.expires) {
+		return s.token, nil
+	}
+	if s.token != "" && s.token != stale {
+		// Expired rather than rejected, so the BMC may still hold it.
+		sm.logout(ctx, s.token, s.location)
+	}
+	s.token, s.location = "", ""
+
+	token, location, err := sm.login(ctx, xname)
+	if err != nil {
+		return "", err
+	}
+	lifetime := sm.Lifetime
+	if lifetime == 0 {
+		lifetime = DefaultSessionLifetime
+	}
+	s.token, s.location, s.expires = token, location, sm.clock().Add(lifetime)
+	return token, nil
+}
+
+// Drop the cached session for an xname without logging out, e.g. because
+// the BMC has already discarded it.
+func (sm *SessionManager) Invalidate(xname string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.sessions, xname)
+}
+
+// Create a session on an xname's BMC with its stored credentials.
+func (sm *SessionManager) login(ctx context.Context, xname string) (string, string, error) {
+	cred, err := sm.Store.GetCompCred(xname)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get credentials for %s: %v", xname, err)
+	}
+	if cred.Xname == "" {
+		return "", "", fmt.Errorf("no credentials stored for %s", xname)
+	}
+	base := CredBaseURL(cred)
+	if base == "" {
+		return "", "", fmt.Errorf("no URL stored for %s", xname)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"UserName": cred.Username,
+		"Password": cred.Password,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+sessionsPath, bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sm.client().Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create session on %s: %v", xname, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("unable to create session on %s: %s", xname, resp.Status)
+	}
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		return "", "", fmt.Errorf("session created on %s has no X-Auth-Token", xname)
+	}
+
+	// Sessions are deleted through their Location, falling back to the
+	// @odata.id in the body. Either may be relative to the BMC.
+	location := resp.Header.Get("Location")
+	if location == "" {
+		var sess struct {
+			ID string `json:"@odata.id"`
+		}
+		json.NewDecoder(resp.Body).Decode(&sess)
+		location = sess.ID
+	}
+	if location != "" {
+		if u, err := url.Parse(base); err == nil {
+			if ref, err := u.Parse(location); err == nil {
+				location = ref.String()
+			}
+		}
+	}
+	return token, location, nil
+}
+
+// Delete a session. A session that is already gone is not an error.
+func (sm *SessionManager) logout(ctx context.Context, token, location string) error {
+	if location == "" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, location, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	resp, err := sm.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to delete session %s: %v", location, err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusUnauthorized:
+		return nil
+	}
+	return fmt.Errorf("unable to delete session %s: %s", location, resp.Status)
+}
+
+// Log out of every session. The SessionManager may be used again
+// afterwards, and logs in again when needed.
+func (sm *SessionManager) Close(ctx context.Context) error {
+	sm.mu.Lock()
+	sessions := sm.sessions
+	sm.sessions = nil
+	sm.mu.Unlock()
+
+	var errs []error
+	for _, s := range sessions {
+		s.mu.Lock()
+		if s.token != "" {
+			if err := sm.logout(ctx, s.token, s.location); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		s.token, s.location = "", ""
+		s.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Get an http.RoundTripper that authenticates requests with the session
+// token of the xname each request resolves to. If the BMC answers 401 the
+// session is replaced and the request retried once. Requests that already
+// carry an Authorization or X-Auth-Token header are passed through
+// unchanged. A nil base means http.DefaultTransport.
+func (sm *SessionManager) Transport(resolver Resolver, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &sessionTransport{sm: sm, resolver: resolver, base: base}
+}
+
+type sessionTransport struct {
+	sm       *SessionManager
+	resolver Resolver
+	base     http.RoundTripper
+}
+
+func (st *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" || req.Header.Get("X-Auth-Token") != "" {
+		return st.base.RoundTrip(req)
+	}
+
+	xname, err := st.resolver.Resolve(req)
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+	token, err := st.sm.token(req.Context(), xname, "")
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	resp, err := st.base.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request can only be sent again if its body can be.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	fresh, err := st.sm.token(req.Context(), xname, token)
+	if err != nil {
+		return resp, nil
+	}
+	retry, err := rewind(req)
+	if err != nil {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return st.base.RoundTrip(withToken(retry, fresh))
+}
+
+// Copy a request, adding a session token.
+func withToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("X-Auth-Token", token)
+	return r
+}
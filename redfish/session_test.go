@@ -0,0 +1,197 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+	"github.com/Cray-HPE/hms-compcredentials/redfishtest"
+)
+
+func setupSessions(t *testing.T) (*redfishtest.Server, *cc.CompCredStore, *SessionManager) {
+	bmc := redfishtest.NewServer()
+	t.Cleanup(bmc.Close)
+	bmc.AddAccount("root", "initial0", "Administrator")
+
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	err := ccs.StoreCompCred(cc.CompCredentials{
+		Xname:    "x0c0s0b0",
+		URL:      bmc.URL + "/redfish/v1",
+		Username: "root",
+		Password: "initial0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bmc, ccs, NewSessionManager(ccs)
+}
+
+func TestSessionToken(t *testing.T) {
+	bmc, _, sm := setupSessions(t)
+	ctx := context.Background()
+
+	token, err := sm.Token(ctx, "x0c0s0b0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := sm.Token(ctx, "x0c0s0b0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" || again != token {
+		t.Errorf("Expected the cached token to be reused; got '%s' then '%s'", token, again)
+	}
+	if bmc.Logins() != 1 {
+		t.Errorf("Expected 1 login, got %d", bmc.Logins())
+	}
+
+	if _, err := sm.Token(ctx, "x9c9s9b9"); err == nil {
+		t.Errorf("Expected an error for an xname with no credentials")
+	}
+}
+
+func TestSessionLoginFailure(t *testing.T) {
+	bmc, _, sm := setupSessions(t)
+	bmc.SetPassword("root", "changed0")
+
+	_, err := sm.Token(context.Background(), "x0c0s0b0")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected a 401 error, got %v", err)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	bmc, _, sm := setupSessions(t)
+	ctx := context.Background()
+	now := time.Now()
+	sm.now = func() time.Time { return now }
+	sm.Lifetime = time.Minute
+
+	first, err := sm.Token(ctx, "x0c0s0b0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	second, err := sm.Token(ctx, "x0c0s0b0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Errorf("Expected a new token after the session lifetime")
+	}
+	if bmc.SessionCount() != 1 {
+		t.Errorf("Expected the expired session to be deleted, %d sessions open", bmc.SessionCount())
+	}
+}
+
+func TestSessionTransport(t *testing.T) {
+	bmc, _, sm := setupSessions(t)
+	client := &http.Client{Transport: sm.Transport(HostResolver{bmc.Listener.Addr().String(): "x0c0s0b0"}, nil)}
+
+	get := func() int {
+		resp, err := client.Get(bmc.URL + "/redfish/v1/Systems")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get(); code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", code)
+	}
+	if code := get(); code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", code)
+	}
+	if bmc.Logins() != 1 {
+		t.Errorf("Expected 1 login, got %d", bmc.Logins())
+	}
+
+	// The BMC times the session out; the transport logs in again.
+	bmc.ExpireSessions()
+	if code := get(); code != http.StatusOK {
+		t.Errorf("Expected 200 after the session expired, got %d", code)
+	}
+	if bmc.Logins() != 2 {
+		t.Errorf("Expected 2 logins, got %d", bmc.Logins())
+	}
+}
+
+func TestSessionTransportConcurrent(t *testing.T) {
+	bmc, _, sm := setupSessions(t)
+	client := &http.Client{Transport: sm.Transport(HostResolver{bmc.Listener.Addr().String(): "x0c0s0b0"}, nil)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(bmc.URL + "/redfish/v1/Managers")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected 200, got %d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+	if bmc.Logins() != 1 {
+		t.Errorf("Expected concurrent requests to share 1 login, got %d", bmc.Logins())
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	bmc, _, sm := setupSessions(t)
+	ctx := context.Background()
+
+	if _, err := sm.Token(ctx, "x0c0s0b0"); err != nil {
+		t.Fatal(err)
+	}
+	if bmc.SessionCount() != 1 {
+		t.Fatalf("Expected 1 session, got %d", bmc.SessionCount())
+	}
+	if err := sm.Close(ctx); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if bmc.SessionCount() != 0 {
+		t.Errorf("Expected Close to delete the session, %d left", bmc.SessionCount())
+	}
+
+	// Closing twice is harmless, and the manager can log in again.
+	if err := sm.Close(ctx); err != nil {
+		t.Errorf("Second Close failed: %v", err)
+	}
+	if _, err := sm.Token(ctx, "x0c0s0b0"); err != nil {
+		t.Errorf("Token after Close failed: %v", err)
+	}
+}
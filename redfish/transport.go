@@ -0,0 +1,191 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// How long Transport caches credentials when CacheTTL is not set.
+const DefaultCacheTTL = 5 * time.Minute
+
+type credEntry struct {
+	cred    cc.CompCredentials
+	expires time.Time
+}
+
+// Transport is an http.RoundTripper that adds basic authentication to each
+// request from the stored credentials of the xname the request resolves to.
+// If the BMC answers 401, the credentials are read again from the store, in
+// case they were rotated, and the request is retried once with them.
+//
+// Requests that already carry an Authorization header are passed through
+// unchanged.
+type Transport struct {
+	Store    *cc.CompCredStore
+	Resolver Resolver
+
+	// The transport requests are sent on. Defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+
+	// How long credentials are cached. Defaults to DefaultCacheTTL.
+	CacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]credEntry
+	now   func() time.Time
+}
+
+// Create a new Transport.
+func NewTransport(ccs *cc.CompCredStore, resolver Resolver) *Transport {
+	return &Transport{
+		Store:    ccs,
+		Resolver: resolver,
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// Get the credentials for an xname, from the cache unless refresh is set.
+func (t *Transport) credentials(xname string, refresh bool) (cc.CompCredentials, error) {
+	ttl := t.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	t.mu.Lock()
+	if t.cache == nil {
+		t.cache = make(map[string]credEntry)
+	}
+	entry, ok := t.cache[xname]
+	t.mu.Unlock()
+	if ok && !refresh && t.clock().Before(entry.expires) {
+		return entry.cred, nil
+	}
+
+	cred, err := t.Store.GetCompCred(xname)
+	if err != nil {
+		return cred, fmt.Errorf("unable to get credentials for %s: %v", xname, err)
+	}
+	if cred.Xname == "" {
+		return cred, fmt.Errorf("no credentials stored for %s", xname)
+	}
+
+	t.mu.Lock()
+	t.cache[xname] = credEntry{cred: cred, expires: t.clock().Add(ttl)}
+	t.mu.Unlock()
+	return cred, nil
+}
+
+// Drop the cached credentials for an xname.
+func (t *Transport) Invalidate(xname string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.cache, xname)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	xname, err := t.Resolver.Resolve(req)
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+	cred, err := t.credentials(xname, false)
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	resp, err := t.base().RoundTrip(withBasicAuth(req, cred))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request can only be sent again if its body can be.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	fresh, err := t.credentials(xname, true)
+	if err != nil || (fresh.Username == cred.Username && fresh.Password == cred.Password) {
+		return resp, nil
+	}
+
+	retry, err := rewind(req)
+	if err != nil {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.base().RoundTrip(withBasicAuth(retry, fresh))
+}
+
+// Copy a request, adding basic authentication. RoundTrippers must not modify
+// the request they are given.
+func withBasicAuth(req *http.Request, cred cc.CompCredentials) *http.Request {
+	r := req.Clone(req.Context())
+	r.SetBasicAuth(cred.Username, cred.Password)
+	return r
+}
+
+// Copy a request with a fresh body so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// RoundTrippers must close the request body, even on errors.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
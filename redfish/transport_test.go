@@ -0,0 +1,166 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfish
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+// countingStorage counts Lookups so tests can see cache hits.
+type countingStorage struct {
+	*conformance.MemoryStorage
+	mu      sync.Mutex
+	lookups int
+}
+
+func (cs *countingStorage) Lookup(key string, output interface{}) error {
+	cs.mu.Lock()
+	cs.lookups++
+	cs.mu.Unlock()
+	return cs.MemoryStorage.Lookup(key, output)
+}
+
+func (cs *countingStorage) count() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.lookups
+}
+
+// A BMC that accepts one username and password and echoes request bodies.
+type basicAuthBMC struct {
+	mu       sync.Mutex
+	password string
+	requests int
+}
+
+func (b *basicAuthBMC) setPassword(p string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.password = p
+}
+
+func (b *basicAuthBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	b.requests++
+	password := b.password
+	b.mu.Unlock()
+
+	user, pass, ok := r.BasicAuth()
+	if !ok || user != "root" || pass != password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	w.Write(body)
+}
+
+func TestTransport(t *testing.T) {
+	bmc := &basicAuthBMC{password: "initial0"}
+	ts := httptest.NewServer(bmc)
+	defer ts.Close()
+
+	ss := &countingStorage{MemoryStorage: conformance.NewMemoryStorage()}
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", URL: ts.URL, Username: "root", Password: "initial0"})
+
+	resolver, _ := NewURLResolver(ccs)
+	now := time.Now()
+	tr := NewTransport(ccs, resolver)
+	tr.now = func() time.Time { return now }
+	client := &http.Client{Transport: tr}
+
+	var tests = []struct {
+		bmcPassword   string
+		storePassword string
+		advance       time.Duration
+		status        int
+		lookups       int
+	}{
+		// First request reads the store.
+		{"initial0", "", 0, http.StatusOK, 1},
+		// Second is served from the cache.
+		{"initial0", "", 0, http.StatusOK, 0},
+		// Rotated on both: 401, re-read, retried with the new password.
+		{"rotated1", "rotated1", 0, http.StatusOK, 1},
+		// Changed on the BMC only: the re-read finds nothing new.
+		{"rotated2", "", 0, http.StatusUnauthorized, 1},
+		// Cache expiry picks up a new stored password.
+		{"rotated2", "rotated2", 2 * DefaultCacheTTL, http.StatusOK, 1},
+	}
+
+	for i, test := range tests {
+		bmc.setPassword(test.bmcPassword)
+		if test.storePassword != "" {
+			ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", URL: ts.URL, Username: "root", Password: test.storePassword})
+		}
+		now = now.Add(test.advance)
+		before := ss.count()
+
+		resp, err := client.Post(ts.URL+"/redfish/v1/Systems", "text/plain", strings.NewReader("body"))
+		if err != nil {
+			t.Fatalf("Test %v Failed: Request error - %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v", i, test.status, resp.StatusCode)
+		}
+		if test.status == http.StatusOK && string(body) != "body" {
+			t.Errorf("Test %v Failed: Expected the request body to be sent but got %q", i, body)
+		}
+		if lookups := ss.count() - before; lookups != test.lookups {
+			t.Errorf("Test %v Failed: Expected %v store lookups but got %v", i, test.lookups, lookups)
+		}
+	}
+}
+
+func TestTransportPassThrough(t *testing.T) {
+	bmc := &basicAuthBMC{password: "explicit"}
+	ts := httptest.NewServer(bmc)
+	defer ts.Close()
+
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	tr := NewTransport(ccs, HostResolver{})
+	client := &http.Client{Transport: tr}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.SetBasicAuth("root", "explicit")
+	resp, err := client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected an explicit Authorization header to be passed through but got %v, %v", resp, err)
+	}
+
+	if _, err := client.Get(ts.URL); err == nil {
+		t.Errorf("Expected an error for a host the resolver does not know")
+	}
+}
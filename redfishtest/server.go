@@ -0,0 +1,543 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package redfishtest provides a fake Redfish BMC for tests. It implements
+// basic and session (X-Auth-Token) authentication, the SessionService and
+// the AccountService, plus a few read-only resources to make authenticated
+// requests against.
+package redfishtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Account is a BMC user account.
+type Account struct {
+	Id       string
+	UserName string
+	Password string
+	RoleId   string
+	Enabled  bool
+}
+
+type session struct {
+	id    string
+	token string
+	user  string
+}
+
+// Server is a fake Redfish BMC backed by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	mux      *http.ServeMux
+	accounts map[string]*Account
+	sessions map[string]*session
+	nextID   int
+	timeout  int
+	logins   int
+	requests map[string]int
+
+	minLength int
+	maxLength int
+	oem       map[string]interface{}
+	intercept func(w http.ResponseWriter, r *http.Request) bool
+}
+
+func newServer() *Server {
+	s := &Server{
+		mux:       http.NewServeMux(),
+		accounts:  make(map[string]*Account),
+		sessions:  make(map[string]*session),
+		timeout:   1800,
+		requests:  make(map[string]int),
+		minLength: 8,
+		maxLength: 20,
+	}
+	s.mux.HandleFunc("GET /redfish/v1/{$}", s.serviceRoot)
+	s.mux.HandleFunc("GET /redfish/v1/SessionService", s.authenticated(s.sessionService))
+	s.mux.HandleFunc("POST /redfish/v1/SessionService/Sessions", s.createSession)
+	s.mux.HandleFunc("GET /redfish/v1/SessionService/Sessions", s.authenticated(s.listSessions))
+	s.mux.HandleFunc("GET /redfish/v1/SessionService/Sessions/{id}", s.authenticated(s.getSession))
+	s.mux.HandleFunc("DELETE /redfish/v1/SessionService/Sessions/{id}", s.authenticated(s.deleteSession))
+	s.mux.HandleFunc("GET /redfish/v1/AccountService", s.authenticated(s.accountService))
+	s.mux.HandleFunc("GET /redfish/v1/AccountService/Accounts", s.authenticated(s.listAccounts))
+	s.mux.HandleFunc("POST /redfish/v1/AccountService/Accounts", s.authenticated(s.createAccount))
+	s.mux.HandleFunc("GET /redfish/v1/AccountService/Accounts/{id}", s.authenticated(s.getAccount))
+	s.mux.HandleFunc("DELETE /redfish/v1/AccountService/Accounts/{id}", s.authenticated(s.deleteAccount))
+	s.mux.HandleFunc("PATCH /redfish/v1/AccountService/Accounts/{id}", s.authenticated(s.patchAccount))
+	s.mux.HandleFunc("GET /redfish/v1/Systems", s.authenticated(s.collection("Systems", "Self")))
+	s.mux.HandleFunc("GET /redfish/v1/Managers", s.authenticated(s.collection("Managers", "BMC")))
+	return s
+}
+
+// Start a fake BMC over plain HTTP.
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Start a fake BMC over HTTPS with a self-signed certificate. Use the
+// Server's Client() to trust it.
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.Method+" "+r.URL.Path]++
+	intercept := s.intercept
+	s.mu.Unlock()
+	if intercept != nil && intercept(w, r) {
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Add an enabled account and return its Id.
+func (s *Server) AddAccount(user, password, role string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	s.accounts[user] = &Account{Id: id, UserName: user, Password: password, RoleId: role, Enabled: true}
+	return id
+}
+
+// Get a copy of an account.
+func (s *Server) Account(user string) (Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[user]
+	if !ok {
+		return Account{}, false
+	}
+	return *a, true
+}
+
+// Change an account's password directly, as if someone changed it on the BMC.
+func (s *Server) SetPassword(user, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.accounts[user]; ok {
+		a.Password = password
+	}
+}
+
+// Set the password lengths the AccountService enforces. The defaults are 8
+// and 20.
+func (s *Server) SetPasswordPolicy(minLength, maxLength int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minLength, s.maxLength = minLength, maxLength
+}
+
+// Set the Oem object the AccountService reports, e.g. vendor password
+// rules.
+func (s *Server) SetAccountServiceOem(oem map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.oem = oem
+}
+
+// Install a function that sees every request first, to inject faults. If it
+// returns true it has written the response and the request goes no further.
+func (s *Server) Intercept(f func(w http.ResponseWriter, r *http.Request) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.intercept = f
+}
+
+// Set the SessionTimeout the SessionService reports, in seconds.
+func (s *Server) SetSessionTimeout(seconds int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeout = seconds
+}
+
+// Get the number of open sessions.
+func (s *Server) SessionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// Get the number of sessions created so far.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Drop every session, as a BMC does when they time out.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]*session)
+}
+
+// Get the number of requests made with a method and path, e.g.
+// "DELETE /redfish/v1/SessionService/Sessions/1".
+func (s *Server) Requests(methodPath string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[methodPath]
+}
+
+// Identify the account making a request from its session token or basic
+// authentication. The caller must hold the lock.
+func (s *Server) authenticate(r *http.Request) (*Account, bool) {
+	if token := r.Header.Get("X-Auth-Token"); token != "" {
+		for _, sess := range s.sessions {
+			if sess.token == token {
+				a, ok := s.accounts[sess.user]
+				return a, ok && a.Enabled
+			}
+		}
+		return nil, false
+	}
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return nil, false
+	}
+	a, ok := s.accounts[user]
+	if !ok || !a.Enabled || a.Password != pass {
+		return nil, false
+	}
+	return a, true
+}
+
+type authHandler func(w http.ResponseWriter, r *http.Request, a *Account)
+
+// Wrap a handler so it is only called for authenticated requests. The
+// handler runs with the lock held.
+func (s *Server) authenticated(h authHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		a, ok := s.authenticate(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Base.1.0.NoValidSession")
+			return
+		}
+		h(w, r, a)
+	}
+}
+
+func (s *Server) serviceRoot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"@odata.id":      "/redfish/v1/",
+		"Id":             "RootService",
+		"RedfishVersion": "1.6.0",
+		"SessionService": odataID("/redfish/v1/SessionService"),
+		"Systems":        odataID("/redfish/v1/Systems"),
+		"Managers":       odataID("/redfish/v1/Managers"),
+		"Links": map[string]interface{}{
+			"Sessions": odataID("/redfish/v1/SessionService/Sessions"),
+		},
+	})
+}
+
+func (s *Server) sessionService(w http.ResponseWriter, r *http.Request, a *Account) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"@odata.id":      "/redfish/v1/SessionService",
+		"ServiceEnabled": true,
+		"SessionTimeout": s.timeout,
+		"Sessions":       odataID("/redfish/v1/SessionService/Sessions"),
+	})
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserName string
+		Password string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Base.1.0.MalformedJSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[req.UserName]
+	if !ok || !a.Enabled || a.Password != req.Password {
+		writeError(w, http.StatusUnauthorized, "Base.1.0.InvalidCredentials")
+		return
+	}
+
+	s.nextID++
+	s.logins++
+	sess := &session{id: strconv.Itoa(s.nextID), token: newToken(), user: a.UserName}
+	s.sessions[sess.id] = sess
+
+	location := "/redfish/v1/SessionService/Sessions/" + sess.id
+	w.Header().Set("X-Auth-Token", sess.token)
+	w.Header().Set("Location", location)
+	writeJSON(w, http.StatusCreated, sessionBody(sess))
+}
+
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request, a *Account) {
+	var members []interface{}
+	for id := range s.sessions {
+		members = append(members, odataID("/redfish/v1/SessionService/Sessions/"+id))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"@odata.id":           "/redfish/v1/SessionService/Sessions",
+		"Members":             members,
+		"Members@odata.count": len(members),
+	})
+}
+
+func (s *Server) getSession(w http.ResponseWriter, r *http.Request, a *Account) {
+	sess, ok := s.sessions[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Base.1.0.ResourceMissingAtURI")
+		return
+	}
+	writeJSON(w, http.StatusOK, sessionBody(sess))
+}
+
+func (s *Server) deleteSession(w http.ResponseWriter, r *http.Request, a *Account) {
+	id := r.PathValue("id")
+	if _, ok := s.sessions[id]; !ok {
+		writeError(w, http.StatusNotFound, "Base.1.0.ResourceMissingAtURI")
+		return
+	}
+	delete(s.sessions, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) accountService(w http.ResponseWriter, r *http.Request, a *Account) {
+	body := map[string]interface{}{
+		"@odata.id":         "/redfish/v1/AccountService",
+		"ServiceEnabled":    true,
+		"MinPasswordLength": s.minLength,
+		"MaxPasswordLength": s.maxLength,
+		"Accounts":          odataID("/redfish/v1/AccountService/Accounts"),
+	}
+	if s.oem != nil {
+		body["Oem"] = s.oem
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request, a *Account) {
+	ids := make([]int, 0, len(s.accounts))
+	for _, acct := range s.accounts {
+		id, _ := strconv.Atoi(acct.Id)
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	members := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, odataID("/redfish/v1/AccountService/Accounts/"+strconv.Itoa(id)))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"@odata.id":           "/redfish/v1/AccountService/Accounts",
+		"Members":             members,
+		"Members@odata.count": len(members),
+	})
+}
+
+// Find an account by Id. The caller must hold the lock.
+func (s *Server) accountByID(id string) *Account {
+	for _, acct := range s.accounts {
+		if acct.Id == id {
+			return acct
+		}
+	}
+	return nil
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request, a *Account) {
+	acct := s.accountByID(r.PathValue("id"))
+	if acct == nil {
+		writeError(w, http.StatusNotFound, "Base.1.0.ResourceMissingAtURI")
+		return
+	}
+	writeJSON(w, http.StatusOK, accountBody(acct))
+}
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request, a *Account) {
+	if a.RoleId != "Administrator" {
+		writeError(w, http.StatusForbidden, "Base.1.0.InsufficientPrivilege")
+		return
+	}
+	var req struct {
+		UserName string
+		Password string
+		RoleId   string
+		Enabled  *bool
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserName == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.0.MalformedJSON")
+		return
+	}
+	if _, ok := s.accounts[req.UserName]; ok {
+		writeError(w, http.StatusConflict, "Base.1.0.ResourceAlreadyExists")
+		return
+	}
+	if len(req.Password) < s.minLength || len(req.Password) > s.maxLength {
+		writeError(w, http.StatusBadRequest, "Base.1.0.PropertyValueFormatError")
+		return
+	}
+	if req.RoleId == "" {
+		req.RoleId = "ReadOnly"
+	}
+
+	s.nextID++
+	acct := &Account{
+		Id:       strconv.Itoa(s.nextID),
+		UserName: req.UserName,
+		Password: req.Password,
+		RoleId:   req.RoleId,
+		Enabled:  req.Enabled == nil || *req.Enabled,
+	}
+	s.accounts[acct.UserName] = acct
+	w.Header().Set("Location", "/redfish/v1/AccountService/Accounts/"+acct.Id)
+	writeJSON(w, http.StatusCreated, accountBody(acct))
+}
+
+func (s *Server) deleteAccount(w http.ResponseWriter, r *http.Request, a *Account) {
+	if a.RoleId != "Administrator" {
+		writeError(w, http.StatusForbidden, "Base.1.0.InsufficientPrivilege")
+		return
+	}
+	acct := s.accountByID(r.PathValue("id"))
+	if acct == nil {
+		writeError(w, http.StatusNotFound, "Base.1.0.ResourceMissingAtURI")
+		return
+	}
+	delete(s.accounts, acct.UserName)
+	for id, sess := range s.sessions {
+		if sess.user == acct.UserName {
+			delete(s.sessions, id)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) patchAccount(w http.ResponseWriter, r *http.Request, a *Account) {
+	acct := s.accountByID(r.PathValue("id"))
+	if acct == nil {
+		writeError(w, http.StatusNotFound, "Base.1.0.ResourceMissingAtURI")
+		return
+	}
+	if acct != a && a.RoleId != "Administrator" {
+		writeError(w, http.StatusForbidden, "Base.1.0.InsufficientPrivilege")
+		return
+	}
+	var req struct {
+		Password *string
+		RoleId   *string
+		Enabled  *bool
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Base.1.0.MalformedJSON")
+		return
+	}
+	if req.Password != nil {
+		if len(*req.Password) < s.minLength || len(*req.Password) > s.maxLength {
+			writeError(w, http.StatusBadRequest, "Base.1.0.PropertyValueFormatError")
+			return
+		}
+		acct.Password = *req.Password
+	}
+	if req.RoleId != nil {
+		acct.RoleId = *req.RoleId
+	}
+	if req.Enabled != nil {
+		acct.Enabled = *req.Enabled
+	}
+	writeJSON(w, http.StatusOK, accountBody(acct))
+}
+
+func (s *Server) collection(name, member string) authHandler {
+	return func(w http.ResponseWriter, r *http.Request, a *Account) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":           "/redfish/v1/" + name,
+			"Members":             []interface{}{odataID("/redfish/v1/" + name + "/" + member)},
+			"Members@odata.count": 1,
+		})
+	}
+}
+
+func accountBody(acct *Account) map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id": "/redfish/v1/AccountService/Accounts/" + acct.Id,
+		"Id":        acct.Id,
+		"UserName":  acct.UserName,
+		"RoleId":    acct.RoleId,
+		"Enabled":   acct.Enabled,
+		"Password":  nil,
+	}
+}
+
+func sessionBody(sess *session) map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id": "/redfish/v1/SessionService/Sessions/" + sess.id,
+		"Id":        sess.id,
+		"UserName":  sess.user,
+	}
+}
+
+func newToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func odataID(id string) map[string]interface{} {
+	return map[string]interface{}{"@odata.id": id}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Write a Redfish error response carrying a Base registry message id.
+func writeError(w http.ResponseWriter, status int, messageID string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    messageID,
+			"message": http.StatusText(status),
+			"@Message.ExtendedInfo": []interface{}{
+				map[string]interface{}{"MessageId": messageID},
+			},
+		},
+	})
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package redfishtest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, s *Server, method, path, body string, auth func(*http.Request)) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth != nil {
+		auth(req)
+	}
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestAuthentication(t *testing.T) {
+	s := NewTLSServer()
+	defer s.Close()
+	s.AddAccount("root", "initial0", "Administrator")
+
+	basic := func(user, pass string) func(*http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth(user, pass) }
+	}
+
+	if resp := do(t, s, "GET", "/redfish/v1/", "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the service root without authentication but got %d", resp.StatusCode)
+	}
+	if resp := do(t, s, "GET", "/redfish/v1/Systems", "", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without authentication but got %d", resp.StatusCode)
+	}
+	if resp := do(t, s, "GET", "/redfish/v1/Systems", "", basic("root", "wrong")); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with a wrong password but got %d", resp.StatusCode)
+	}
+	if resp := do(t, s, "GET", "/redfish/v1/Systems", "", basic("root", "initial0")); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with basic authentication but got %d", resp.StatusCode)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddAccount("root", "initial0", "Administrator")
+
+	resp := do(t, s, "POST", "/redfish/v1/SessionService/Sessions", `{"UserName":"root","Password":"wrong"}`, nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong password but got %d", resp.StatusCode)
+	}
+
+	resp = do(t, s, "POST", "/redfish/v1/SessionService/Sessions", `{"UserName":"root","Password":"initial0"}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 but got %d", resp.StatusCode)
+	}
+	token, location := resp.Header.Get("X-Auth-Token"), resp.Header.Get("Location")
+	if token == "" || !strings.HasPrefix(location, "/redfish/v1/SessionService/Sessions/") {
+		t.Fatalf("Expected a token and location but got %q, %q", token, location)
+	}
+	withToken := func(r *http.Request) { r.Header.Set("X-Auth-Token", token) }
+
+	if resp := do(t, s, "GET", "/redfish/v1/Managers", "", withToken); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with the session token but got %d", resp.StatusCode)
+	}
+	if s.SessionCount() != 1 || s.Logins() != 1 {
+		t.Errorf("Expected 1 session and 1 login but got %d, %d", s.SessionCount(), s.Logins())
+	}
+	if resp := do(t, s, "DELETE", location, "", withToken); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204 deleting the session but got %d", resp.StatusCode)
+	}
+	if resp := do(t, s, "GET", "/redfish/v1/Managers", "", withToken); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with a deleted session but got %d", resp.StatusCode)
+	}
+	if n := s.Requests("DELETE " + location); n != 1 {
+		t.Errorf("Expected 1 DELETE request but got %d", n)
+	}
+}
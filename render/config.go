@@ -0,0 +1,126 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Signals that may be named in a config file.
+var signalNames = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// The JSON form of a Renderer's settings.
+type fileConfig struct {
+	Interval  string `json:"interval"`
+	Templates []struct {
+		Source   string   `json:"source"`
+		Dest     string   `json:"dest"`
+		Mode     string   `json:"mode"`
+		Xnames   []string `json:"xnames"`
+		PerXname bool     `json:"perXname"`
+		Reload   struct {
+			Command []string `json:"command"`
+			Signal  string   `json:"signal"`
+			PIDFile string   `json:"pidFile"`
+		} `json:"reload"`
+	} `json:"templates"`
+}
+
+// Read the interval and templates for a Renderer from a JSON file:
+//
+//	{
+//	  "interval": "30s",
+//	  "templates": [{
+//	    "source": "/etc/conman/conman.conf.tmpl",
+//	    "dest": "/etc/conman/conman.conf",
+//	    "mode": "0600",
+//	    "xnames": ["x1000c0s1b0"],
+//	    "reload": {"signal": "SIGHUP", "pidFile": "/run/conmand.pid"}
+//	  }]
+//	}
+func LoadConfig(path string) (time.Duration, []Template, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	var fc fileConfig
+	dec := json.NewDecoder(strings.NewReader(string(buf)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return 0, nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	interval := DefaultInterval
+	if fc.Interval != "" {
+		if interval, err = time.ParseDuration(fc.Interval); err != nil {
+			return 0, nil, fmt.Errorf("%s: invalid interval: %v", path, err)
+		}
+	}
+
+	var templates []Template
+	for i, ft := range fc.Templates {
+		if ft.Source == "" || ft.Dest == "" {
+			return 0, nil, fmt.Errorf("%s: template %d needs a source and a dest", path, i)
+		}
+		t := Template{
+			Source:   ft.Source,
+			Dest:     ft.Dest,
+			Xnames:   ft.Xnames,
+			PerXname: ft.PerXname,
+			Reload: Reload{
+				Command: ft.Reload.Command,
+				PIDFile: ft.Reload.PIDFile,
+			},
+		}
+		if ft.Mode != "" {
+			mode, err := strconv.ParseUint(ft.Mode, 8, 32)
+			if err != nil || mode > 0777 {
+				return 0, nil, fmt.Errorf("%s: template %d has invalid mode %q", path, i, ft.Mode)
+			}
+			t.Mode = os.FileMode(mode)
+		}
+		if ft.Reload.Signal != "" {
+			sig, ok := signalNames[strings.ToUpper(ft.Reload.Signal)]
+			if !ok {
+				return 0, nil, fmt.Errorf("%s: template %d has unknown signal %q", path, i, ft.Reload.Signal)
+			}
+			if ft.Reload.PIDFile == "" {
+				return 0, nil, fmt.Errorf("%s: template %d has a reload signal but no pidFile", path, i)
+			}
+			t.Reload.Signal = sig
+		}
+		templates = append(templates, t)
+	}
+
+	return interval, templates, nil
+}
@@ -0,0 +1,339 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package render writes component credentials into other services' config
+// files. Each Template is a Go text/template filled from a CompCredStore,
+// either once for a set of xnames or once per xname. Files are written
+// atomically with restrictive permissions, only when their content changes,
+// and a reload command or signal is run after each change.
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// The mode of rendered files when Template.Mode is not set.
+const DefaultMode os.FileMode = 0600
+
+// How often Run re-renders when Renderer.Interval is not set.
+const DefaultInterval = time.Minute
+
+// How long a reload command may run.
+const reloadTimeout = 30 * time.Second
+
+// Reload says how to tell a service that its config file changed. Either
+// Command is run, or Signal is sent to the process whose pid is in PIDFile.
+type Reload struct {
+	Command []string
+	Signal  syscall.Signal
+	PIDFile string
+}
+
+// Template describes one rendered file, or one file per xname.
+type Template struct {
+	// Path of the text/template to render.
+	Source string
+
+	// Path of the output file. When PerXname is set this is itself a
+	// template, executed with the same Data, that names each xname's file.
+	Dest string
+
+	// Mode of the output file. Defaults to DefaultMode.
+	Mode os.FileMode
+
+	// The xnames whose credentials are available. Empty means all.
+	Xnames []string
+
+	// Render one file per xname instead of one file for all of them.
+	PerXname bool
+
+	Reload Reload
+}
+
+// Data is what templates are executed with. Xname and Cred are only set for
+// PerXname templates.
+type Data struct {
+	Xname  string
+	Cred   cc.CompCredentials
+	Xnames []string
+	Creds  map[string]cc.CompCredentials
+}
+
+// Result reports what happened to one output file.
+type Result struct {
+	Dest    string
+	Changed bool
+	Err     error
+}
+
+// Renderer renders a set of templates from a CompCredStore.
+type Renderer struct {
+	Store     *cc.CompCredStore
+	Templates []Template
+	Interval  time.Duration
+
+	// Templates whose files changed but whose reload failed, by Source and
+	// Dest, so the reload is retried on the next pass.
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// Create a new Renderer.
+func NewRenderer(ccs *cc.CompCredStore, templates ...Template) *Renderer {
+	return &Renderer{
+		Store:     ccs,
+		Templates: templates,
+		Interval:  DefaultInterval,
+	}
+}
+
+// Render every template once, writing the files whose content changed and
+// reloading their services. A failed reload is retried on each pass until
+// it succeeds, even if the files do not change again. An error is returned
+// if any template failed; the results say which.
+func (r *Renderer) RenderOnce() ([]Result, error) {
+	var (
+		results []Result
+		failed  int
+	)
+
+	for _, t := range r.Templates {
+		tresults, err := r.render(t)
+		if err != nil {
+			tresults = append(tresults, Result{Dest: t.Dest, Err: err})
+		}
+
+		changed := false
+		for _, res := range tresults {
+			if res.Err != nil {
+				failed++
+			}
+			changed = changed || res.Changed
+		}
+		key := t.Source + "\x00" + t.Dest
+		if changed || r.reloadPending(key) {
+			err := t.Reload.run()
+			if err != nil {
+				log.WithError(err).WithField("source", t.Source).Error("Unable to reload after rendering")
+				tresults = append(tresults, Result{Dest: t.Dest, Err: err})
+				failed++
+			}
+			r.setReloadPending(key, err != nil)
+		}
+		results = append(results, tresults...)
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("%d of the rendered files failed", failed)
+	}
+	return results, nil
+}
+
+func (r *Renderer) reloadPending(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pending[key]
+}
+
+func (r *Renderer) setReloadPending(key string, pending bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !pending {
+		delete(r.pending, key)
+		return
+	}
+	if r.pending == nil {
+		r.pending = make(map[string]bool)
+	}
+	r.pending[key] = true
+}
+
+// Render every template now and then again every Interval until ctx is done.
+func (r *Renderer) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RenderOnce(); err != nil {
+			log.WithError(err).Error("Rendering failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Renderer) render(t Template) ([]Result, error) {
+	src, err := os.ReadFile(t.Source)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(t.Source)).Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	var creds map[string]cc.CompCredentials
+	if len(t.Xnames) == 0 {
+		creds, err = r.Store.GetAllCompCreds()
+	} else {
+		creds, err = r.Store.GetCompCreds(t.Xnames)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// GetCompCreds keys xnames with no stored credentials by "".
+	delete(creds, "")
+	xnames := make([]string, 0, len(creds))
+	for xname := range creds {
+		xnames = append(xnames, xname)
+	}
+	sort.Strings(xnames)
+	mode := t.Mode
+	if mode == 0 {
+		mode = DefaultMode
+	}
+
+	if !t.PerXname {
+		data := Data{Xnames: xnames, Creds: creds}
+		return []Result{renderFile(tmpl, t.Dest, mode, data)}, nil
+	}
+
+	destTmpl, err := template.New("dest").Option("missingkey=error").Parse(t.Dest)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, xname := range xnames {
+		data := Data{Xname: xname, Cred: creds[xname], Xnames: xnames, Creds: creds}
+		var dest strings.Builder
+		if err := destTmpl.Execute(&dest, data); err != nil {
+			results = append(results, Result{Dest: t.Dest, Err: err})
+			continue
+		}
+		results = append(results, renderFile(tmpl, dest.String(), mode, data))
+	}
+	return results, nil
+}
+
+func renderFile(tmpl *template.Template, dest string, mode os.FileMode, data Data) Result {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return Result{Dest: dest, Err: err}
+	}
+
+	if old, err := os.ReadFile(dest); err == nil && bytes.Equal(old, buf.Bytes()) {
+		if fi, err := os.Stat(dest); err == nil && fi.Mode().Perm() == mode.Perm() {
+			return Result{Dest: dest}
+		}
+	}
+
+	if err := writeFileAtomic(dest, buf.Bytes(), mode); err != nil {
+		return Result{Dest: dest, Err: err}
+	}
+	return Result{Dest: dest, Changed: true}
+}
+
+// Write a file by writing a temporary file in the same directory and
+// renaming it over the destination, so readers never see a partial file.
+func writeFileAtomic(dest string, data []byte, mode os.FileMode) error {
+	dir, base := filepath.Split(dest)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := os.CreateTemp(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	// Set the mode before writing anything sensitive.
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+func (rl Reload) run() error {
+	if len(rl.Command) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, rl.Command[0], rl.Command[1:]...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("reload command %q failed: %v: %s", rl.Command[0], err, bytes.TrimSpace(out))
+		}
+		return nil
+	}
+
+	if rl.Signal != 0 {
+		buf, err := os.ReadFile(rl.PIDFile)
+		if err != nil {
+			return err
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(buf)))
+		if err != nil {
+			return fmt.Errorf("%s: invalid pid: %v", rl.PIDFile, err)
+		}
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		return p.Signal(rl.Signal)
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+func newTestStore(t *testing.T) *cc.CompCredStore {
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s1b0", URL: "10.1.1.1", Username: "root", Password: "123"})
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x1000c0s2b0", URL: "10.1.1.2", Username: "admin", Password: "456"})
+	return ccs
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Unable to write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read %s: %v", path, err)
+	}
+	return string(buf)
+}
+
+func TestRenderAll(t *testing.T) {
+	ccs := newTestStore(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "conman.tmpl")
+	dest := filepath.Join(dir, "conman.conf")
+	marker := filepath.Join(dir, "reloads")
+	writeFile(t, src, `{{range .Xnames}}{{with index $.Creds .}}{{.Xname}} {{.Username}}:{{.Password}}
+{{end}}{{end}}`)
+
+	r := NewRenderer(ccs, Template{
+		Source: src,
+		Dest:   dest,
+		Reload: Reload{Command: []string{"sh", "-c", "echo reload >> " + marker}},
+	})
+
+	var tests = []struct {
+		update  *cc.CompCredentials
+		changed bool
+		content string
+		reloads string
+	}{
+		{
+			changed: true,
+			content: "x1000c0s1b0 root:123\nx1000c0s2b0 admin:456\n",
+			reloads: "reload\n",
+		}, {
+			changed: false,
+			content: "x1000c0s1b0 root:123\nx1000c0s2b0 admin:456\n",
+			reloads: "reload\n",
+		}, {
+			update:  &cc.CompCredentials{Xname: "x1000c0s2b0", Username: "admin", Password: "789"},
+			changed: true,
+			content: "x1000c0s1b0 root:123\nx1000c0s2b0 admin:789\n",
+			reloads: "reload\nreload\n",
+		},
+	}
+
+	for i, test := range tests {
+		if test.update != nil {
+			ccs.StoreCompCred(*test.update)
+		}
+		results, err := r.RenderOnce()
+		if err != nil {
+			t.Fatalf("Test %v Failed: Unexpected error - %v", i, err)
+		}
+		if len(results) != 1 || results[0].Changed != test.changed {
+			t.Errorf("Test %v Failed: Expected changed %v but got %+v", i, test.changed, results)
+		}
+		if content := readFile(t, dest); content != test.content {
+			t.Errorf("Test %v Failed: Expected content %q but got %q", i, test.content, content)
+		}
+		if reloads := readFile(t, marker); reloads != test.reloads {
+			t.Errorf("Test %v Failed: Expected reloads %q but got %q", i, test.reloads, reloads)
+		}
+		if fi, _ := os.Stat(dest); fi.Mode().Perm() != DefaultMode {
+			t.Errorf("Test %v Failed: Expected mode %v but got %v", i, DefaultMode, fi.Mode().Perm())
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Errorf("Expected no temporary files to be left behind but found %v entries", len(entries))
+	}
+}
+
+func TestReloadRetry(t *testing.T) {
+	ccs := newTestStore(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "conman.tmpl")
+	marker := filepath.Join(dir, "reloads")
+	broken := filepath.Join(dir, "broken")
+	writeFile(t, src, `{{range .Xnames}}{{.}}
+{{end}}`)
+	writeFile(t, broken, "")
+
+	// The reload fails while the broken file exists.
+	r := NewRenderer(ccs, Template{
+		Source: src,
+		Dest:   filepath.Join(dir, "conman.conf"),
+		Reload: Reload{Command: []string{"sh", "-c", "test ! -e " + broken + " && echo reload >> " + marker}},
+	})
+
+	var tests = []struct {
+		fix     bool
+		err     bool
+		reloads string
+	}{
+		{false, true, ""},
+		{false, true, ""},
+		// Retried once it works, though the file has not changed again.
+		{true, false, "reload\n"},
+		{false, false, "reload\n"},
+	}
+	for i, test := range tests {
+		if test.fix {
+			os.Remove(broken)
+		}
+		if _, err := r.RenderOnce(); (err != nil) != test.err {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.err, err)
+		}
+		reloads, _ := os.ReadFile(marker)
+		if string(reloads) != test.reloads {
+			t.Errorf("Test %v Failed: Expected reloads %q but got %q", i, test.reloads, reloads)
+		}
+	}
+}
+
+func TestRenderPerXname(t *testing.T) {
+	ccs := newTestStore(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "bmc.tmpl")
+	writeFile(t, src, "user={{.Cred.Username}} pass={{.Cred.Password}}\n")
+
+	r := NewRenderer(ccs, Template{
+		Source:   src,
+		Dest:     filepath.Join(dir, "{{.Xname}}.conf"),
+		Mode:     0640,
+		Xnames:   []string{"x1000c0s1b0", "x9999c0s0b0"},
+		PerXname: true,
+	})
+	results, err := r.RenderOnce()
+	if err != nil {
+		t.Fatalf("Unexpected error - %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected one file for the one stored xname but got %+v", results)
+	}
+	dest := filepath.Join(dir, "x1000c0s1b0.conf")
+	if content := readFile(t, dest); content != "user=root pass=123\n" {
+		t.Errorf("Unexpected content %q", content)
+	}
+	if fi, _ := os.Stat(dest); fi.Mode().Perm() != 0640 {
+		t.Errorf("Expected mode 0640 but got %v", fi.Mode().Perm())
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	ccs := newTestStore(t)
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.tmpl")
+	writeFile(t, bad, "{{.Bogus}}")
+
+	r := NewRenderer(ccs,
+		Template{Source: filepath.Join(dir, "missing.tmpl"), Dest: filepath.Join(dir, "a")},
+		Template{Source: bad, Dest: filepath.Join(dir, "b")},
+	)
+	results, err := r.RenderOnce()
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+	for i, res := range results {
+		if res.Err == nil || res.Changed {
+			t.Errorf("Test %v Failed: Expected a failed, unchanged result but got %+v", i, res)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b")); err == nil {
+		t.Errorf("Expected nothing to be written for a failed template")
+	}
+}
+
+func TestReloadSignal(t *testing.T) {
+	ccs := newTestStore(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "t.tmpl")
+	pidFile := filepath.Join(dir, "pid")
+	writeFile(t, src, "{{len .Creds}}\n")
+	writeFile(t, pidFile, strconv.Itoa(os.Getpid())+"\n")
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	r := NewRenderer(ccs, Template{
+		Source: src,
+		Dest:   filepath.Join(dir, "out"),
+		Reload: Reload{Signal: syscall.SIGHUP, PIDFile: pidFile},
+	})
+	if _, err := r.RenderOnce(); err != nil {
+		t.Fatalf("Unexpected error - %v", err)
+	}
+	select {
+	case <-sigs:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected a SIGHUP after rendering")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	var tests = []struct {
+		config  string
+		respErr bool
+	}{
+		{`{"interval":"30s","templates":[{"source":"a","dest":"b","mode":"0640","reload":{"signal":"sighup","pidFile":"p"}}]}`, false},
+		{`{"templates":[{"source":"a"}]}`, true},
+		{`{"templates":[{"source":"a","dest":"b","mode":"999"}]}`, true},
+		{`{"templates":[{"source":"a","dest":"b","reload":{"signal":"SIGHUP"}}]}`, true},
+		{`{"templates":[{"source":"a","dest":"b","reload":{"signal":"SIGBOGUS","pidFile":"p"}}]}`, true},
+		{`{"interval":"soon"}`, true},
+		{`{"bogus":true}`, true},
+	}
+
+	for i, test := range tests {
+		path := filepath.Join(dir, "config.json")
+		writeFile(t, path, test.config)
+		interval, templates, err := LoadConfig(path)
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+		}
+		if i == 0 && err == nil {
+			if interval != 30*time.Second || len(templates) != 1 || templates[0].Mode != 0640 ||
+				templates[0].Reload.Signal != syscall.SIGHUP {
+				t.Errorf("Test %v Failed: Unexpected config %v %+v", i, interval, templates)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// The error StoreCompCred wraps when it refuses a password that was used
+// recently for the same account.
+var ErrPasswordReused = errors.New("password was used recently")
+
+// The shortest key ReusePolicy accepts.
+const MinReuseKeyLength = 32
+
+// ReusePolicy makes StoreCompCred refuse passwords recently used for the
+// same account, the xname and username. Previous passwords are remembered
+// only as salted fingerprints keyed with Key, in the "<CCPath>-reuse" key
+// space, so they cannot be recovered or checked against guesses without the
+// key. The password, SNMPAuthPass and SNMPPrivPass fields are each checked
+// against their own history.
+type ReusePolicy struct {
+	// How many of each account's passwords are remembered, including the
+	// current one.
+	Remember int
+
+	// The secret the fingerprints are keyed with, of at least
+	// MinReuseKeyLength bytes. Changing it forgets every fingerprint.
+	Key []byte
+}
+
+// The form an account's fingerprints are stored in, newest last.
+type reuseRecord struct {
+	Password     []string
+	SNMPAuthPass []string
+	SNMPPrivPass []string
+}
+
+// A field checked for reuse, by JSON name, with its value and its
+// fingerprints.
+type reuseField struct {
+	name         string
+	value        string
+	fingerprints *[]string
+}
+
+func (r *reuseRecord) fields(cred *CompCredentials) []reuseField {
+	return []reuseField{
+		{"password", cred.Password, &r.Password},
+		{"SNMPAuthPass", cred.SNMPAuthPass, &r.SNMPAuthPass},
+		{"SNMPPrivPass", cred.SNMPPrivPass, &r.SNMPPrivPass},
+	}
+}
+
+// Get the key an account's fingerprints are stored at. The username is
+// escaped, and prefixed so that an empty one still has a key.
+func (ccs *CompCredStore) reuseKey(xname, username string) string {
+	return ccs.CCPath + "-reuse/" + xname + "/_" + url.PathEscape(username)
+}
+
+// Fingerprint a password with a salt, as base64 "salt:mac".
+func (p *ReusePolicy) fingerprint(salt []byte, field, password string) string {
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write(salt)
+	mac.Write([]byte(field + "\x00" + password))
+	enc := base64.RawStdEncoding
+	return enc.EncodeToString(salt) + ":" + enc.EncodeToString(mac.Sum(nil))
+}
+
+func (p *ReusePolicy) newFingerprint(field, password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return p.fingerprint(salt, field, password), nil
+}
+
+// Check whether a password matches any of the fingerprints.
+func (p *ReusePolicy) matches(fingerprints []string, field, password string) bool {
+	for _, fp := range fingerprints {
+		encSalt, _, ok := strings.Cut(fp, ":")
+		if !ok {
+			continue
+		}
+		salt, err := base64.RawStdEncoding.DecodeString(encSalt)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal([]byte(p.fingerprint(salt, field, password)), []byte(fp)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check that credentials do not reuse any of their account's remembered
+// passwords, such as before changing a password on the component itself.
+// Passwords that are unchanged from those stored are not reuse. Without a
+// PasswordReuse policy nothing is refused.
+func (ccs *CompCredStore) CheckPasswordReuse(cred CompCredentials) error {
+	_, _, err := ccs.reuseUpdate(cred)
+	return err
+}
+
+// Check credentials for reuse, and get the account's fingerprints as they
+// should be once the credentials are stored. The record is nil if nothing
+// changes.
+func (ccs *CompCredStore) reuseUpdate(cred CompCredentials) (string, *reuseRecord, error) {
+	p := ccs.PasswordReuse
+	if p == nil {
+		return "", nil, nil
+	}
+	if len(p.Key) < MinReuseKeyLength {
+		return "", nil, fmt.Errorf("the password reuse key must be at least %d bytes", MinReuseKeyLength)
+	}
+
+	var existing CompCredentials
+	if err := ccs.SS.Lookup(ccs.CCPath+"/"+cred.Xname, &existing); err != nil {
+		return "", nil, err
+	}
+	key := ccs.reuseKey(cred.Xname, cred.Username)
+	var record reuseRecord
+	if err := ccs.SS.Lookup(key, &record); err != nil {
+		return "", nil, err
+	}
+
+	changed := false
+	oldFields := record.fields(&existing)
+	for i, f := range record.fields(&cred) {
+		old := oldFields[i].value
+		if f.value == "" || (f.value == old && existing.Username == cred.Username) {
+			continue
+		}
+		// Passwords stored before reuse was checked are remembered when
+		// they are replaced.
+		if old != "" && existing.Username == cred.Username && !p.matches(*f.fingerprints, f.name, old) {
+			fp, err := p.newFingerprint(f.name, old)
+			if err != nil {
+				return "", nil, err
+			}
+			*f.fingerprints = append(*f.fingerprints, fp)
+		}
+		if p.matches(*f.fingerprints, f.name, f.value) {
+			return "", nil, fmt.Errorf("%s for %s: %w", f.name, cred.Xname, ErrPasswordReused)
+		}
+
+		fp, err := p.newFingerprint(f.name, f.value)
+		if err != nil {
+			return "", nil, err
+		}
+		*f.fingerprints = append(*f.fingerprints, fp)
+		if n := len(*f.fingerprints) - p.Remember; n > 0 {
+			*f.fingerprints = (*f.fingerprints)[n:]
+		}
+		changed = true
+	}
+	if !changed {
+		return "", nil, nil
+	}
+	return key, &record, nil
+}
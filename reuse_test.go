@@ -0,0 +1,136 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+var reuseKey = bytes.Repeat([]byte("k"), cc.MinReuseKeyLength)
+
+func TestPasswordReuse(t *testing.T) {
+	ss := conformance.NewMemoryStorage()
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, ss)
+	// Stored before reuse was checked.
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "zero"})
+	ccs.PasswordReuse = &cc.ReusePolicy{Remember: 3, Key: reuseKey}
+
+	var tests = []struct {
+		cred   cc.CompCredentials
+		reused bool
+	}{
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "one"}, false},
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "zero"}, true},
+		// Unchanged passwords are not reuse.
+		{cc.CompCredentials{Xname: "x0c0s1b0", URL: "10.4.0.21", Username: "root", Password: "one"}, false},
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "two"}, false},
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "one"}, true},
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "three"}, false},
+		// Only the last three are remembered.
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "zero"}, false},
+		// Another account, or another field, has its own history.
+		{cc.CompCredentials{Xname: "x0c0s1b0", Username: "admin", Password: "three"}, false},
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "three"}, false},
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "three", SNMPAuthPass: "three"}, false},
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "four", SNMPAuthPass: "auth"}, false},
+		{cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "four", SNMPAuthPass: "three"}, true},
+	}
+	for i, test := range tests {
+		err := ccs.StoreCompCred(test.cred)
+		if reused := errors.Is(err, cc.ErrPasswordReused); reused != test.reused || (err != nil && !reused) {
+			t.Errorf("Test %v Failed: Expected reuse %v but got %v", i, test.reused, err)
+		}
+		if err != nil && strings.Contains(err.Error(), test.cred.Password) {
+			t.Errorf("Test %v Failed: Password in error %v", i, err)
+		}
+		stored, _ := ccs.GetCompCred(test.cred.Xname)
+		if (stored == test.cred) == test.reused {
+			t.Errorf("Test %v Failed: Unexpected stored credentials %v", i, stored)
+		}
+	}
+
+	// No plaintext is kept for the check.
+	for _, key := range []string{"x0c0s1b0/_root", "x0c0s1b0/_admin", "x0c0s2b0/_root"} {
+		var record map[string]interface{}
+		ss.Lookup(cc.DefaultCompCredPath+"-reuse/"+key, &record)
+		if record == nil {
+			t.Errorf("Expected fingerprints for %s", key)
+		}
+		dump := fmt.Sprint(record)
+		for _, password := range []string{"zero", "one", "two", "three", "four", "auth"} {
+			if strings.Contains(dump, password) {
+				t.Errorf("Found %q in the fingerprints for %s: %s", password, key, dump)
+			}
+		}
+	}
+
+	// Fingerprints outlive the credentials.
+	ccs.DeleteCompCred("x0c0s2b0")
+	if err := ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s2b0", Username: "root", Password: "three"}); !errors.Is(err, cc.ErrPasswordReused) {
+		t.Errorf("Expected reuse after delete to be refused but got %v", err)
+	}
+
+	ccs.PasswordReuse.Key = []byte("short")
+	if err := ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Password: "five"}); err == nil || errors.Is(err, cc.ErrPasswordReused) {
+		t.Errorf("Expected an error for a short key but got %v", err)
+	}
+}
+
+func TestPasswordReuseRollback(t *testing.T) {
+	ccs := newBundleStore(t)
+	ccs.HistoryLimit = 5
+	ccs.PasswordReuse = &cc.ReusePolicy{Remember: 5, Key: reuseKey}
+	storePasswords(t, ccs, "one", "two")
+
+	if _, err := ccs.RollbackCompCred("x0c0s1b0", 1); err != nil {
+		t.Errorf("Expected a rollback not to be refused but got %v", err)
+	}
+	s, _ := ccs.CurrentSnapshot("before", "")
+	storePasswords(t, ccs, "three")
+	if _, err := ccs.RestoreSnapshot(s, nil, false); err != nil {
+		t.Errorf("Expected a restore not to be refused but got %v", err)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "one" {
+		t.Errorf("Expected the restored password but got %q", cred.Password)
+	}
+
+	var buf bytes.Buffer
+	key := cc.BundleKey{Passphrase: "correct horse"}
+	if _, err := ccs.Export(&buf, key); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	storePasswords(t, ccs, "four")
+	if _, err := ccs.Import(&buf, key, cc.ImportOptions{Conflict: cc.ConflictOverwrite}); err != nil {
+		t.Errorf("Expected an import not to be refused but got %v", err)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "one" {
+		t.Errorf("Expected the imported password but got %q", cred.Password)
+	}
+}
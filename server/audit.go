@@ -0,0 +1,62 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AuditEvent records one request. It never carries credential values; for
+// writes it names the fields that changed.
+type AuditEvent struct {
+	Time       time.Time
+	Principal  string
+	RemoteAddr string
+	Method     string
+	Route      string
+	Xname      string
+	Status     int
+	Fields     []string
+}
+
+// Auditor receives an AuditEvent for every request the server handles.
+type Auditor interface {
+	Audit(event AuditEvent)
+}
+
+// LogAuditor writes audit events to the logrus standard logger.
+type LogAuditor struct{}
+
+func (LogAuditor) Audit(event AuditEvent) {
+	log.WithFields(log.Fields{
+		"principal":  event.Principal,
+		"remoteAddr": event.RemoteAddr,
+		"method":     event.Method,
+		"route":      event.Route,
+		"xname":      event.Xname,
+		"status":     event.Status,
+		"fields":     event.Fields,
+	}).Info("Credential API request")
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// A Scope grants access to a class of routes.
+type Scope string
+
+const (
+	ScopeRead   Scope = "creds:read"
+	ScopeWrite  Scope = "creds:write"
+	ScopeDelete Scope = "creds:delete"
+)
+
+// Principal is an authenticated caller and the scopes it holds.
+type Principal struct {
+	Name   string
+	Scopes []Scope
+}
+
+// Check whether the principal holds a scope.
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator identifies the caller of a request. It returns a nil
+// Principal and no error when the request carries no credentials it
+// recognises, so that the next Authenticator can be tried, and an error when
+// the request carries credentials that are invalid.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// TokenAuthenticator accepts "Authorization: Bearer <token>" headers.
+type TokenAuthenticator struct {
+	tokens []tokenEntry
+}
+
+type tokenEntry struct {
+	token     []byte
+	principal Principal
+}
+
+// Create a TokenAuthenticator from a map of bearer tokens to the principals
+// they identify.
+func NewTokenAuthenticator(tokens map[string]Principal) *TokenAuthenticator {
+	ta := &TokenAuthenticator{}
+	for token, p := range tokens {
+		ta.tokens = append(ta.tokens, tokenEntry{token: []byte(token), principal: p})
+	}
+	return ta
+}
+
+func (ta *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		return nil, nil
+	}
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	// Compare against every token so the time taken does not depend on
+	// which one matched.
+	var found *Principal
+	for i := range ta.tokens {
+		if subtle.ConstantTimeCompare(ta.tokens[i].token, []byte(strings.TrimSpace(token))) == 1 {
+			found = &ta.tokens[i].principal
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("invalid bearer token")
+	}
+	p := *found
+	return &p, nil
+}
+
+// CertAuthenticator accepts TLS client certificates that the server has
+// already verified, identifying the caller by the certificate's common name.
+// The server's tls.Config must set ClientAuth to VerifyClientCertIfGiven or
+// RequireAndVerifyClientCert.
+type CertAuthenticator struct {
+	// Principals by client certificate common name.
+	Subjects map[string]Principal
+}
+
+func (ca *CertAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	p, ok := ca.Subjects[cn]
+	if !ok {
+		return nil, fmt.Errorf("client certificate %q is not authorised", cn)
+	}
+	return &p, nil
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+func TestTokenAuthenticator(t *testing.T) {
+	ta := NewTokenAuthenticator(map[string]Principal{
+		"good": {Name: "svc", Scopes: []Scope{ScopeRead}},
+	})
+
+	var tests = []struct {
+		header  string
+		name    string
+		respErr bool
+	}{
+		{"", "", false},
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"Bearer good", "svc", false},
+		{"bearer good", "svc", false},
+		{"Bearer bad", "", true},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest("GET", "/v1/creds", nil)
+		if test.header != "" {
+			r.Header.Set("Authorization", test.header)
+		}
+		p, err := ta.Authenticate(r)
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+		}
+		name := ""
+		if p != nil {
+			name = p.Name
+		}
+		if name != test.name {
+			t.Errorf("Test %v Failed: Expected principal %q but got %q", i, test.name, name)
+		}
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unable to create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca *testCA) issue(t *testing.T, cn string, serial int64) tls.Certificate {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Unable to issue certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestCertAuthenticator(t *testing.T) {
+	ca := newTestCA(t)
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s1b0", Password: "123"})
+
+	s := NewServer(Config{
+		Store: ccs,
+		Authenticators: []Authenticator{
+			NewTokenAuthenticator(map[string]Principal{
+				adminToken: {Name: "admin", Scopes: []Scope{ScopeRead}},
+			}),
+			&CertAuthenticator{Subjects: map[string]Principal{
+				"conman": {Name: "conman", Scopes: []Scope{ScopeRead}},
+			}},
+		},
+		Auditor: &testAuditor{},
+	})
+	ts := httptest.NewUnstartedServer(s)
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  ca.pool,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	var tests = []struct {
+		cn     string
+		status int
+	}{
+		{"conman", http.StatusOK},
+		{"stranger", http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	}
+	for i, test := range tests {
+		client := ts.Client()
+		transport := client.Transport.(*http.Transport).Clone()
+		if test.cn != "" {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{ca.issue(t, test.cn, int64(i+2))}
+		}
+		client = &http.Client{Transport: transport}
+
+		resp, err := client.Get(ts.URL + "/v1/creds/x0c0s1b0")
+		if err != nil {
+			t.Fatalf("Test %v Failed: Request error - %v", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v", i, test.status, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package server exposes a CompCredStore over HTTP for tools that cannot link
+// this library.
+//
+// Routes:
+//
+//	GET    /v1/creds                 all credentials              creds:read
+//	GET    /v1/creds/{xname}         one component's credentials  creds:read
+//	PUT    /v1/creds/{xname}         replace credentials          creds:write
+//	PATCH  /v1/creds/{xname}         update some fields           creds:write
+//	DELETE /v1/creds/{xname}         remove credentials           creds:delete
+//	GET    /v1/schemas/{name}        JSON schemas for the bodies  (no auth)
+//
+// Callers authenticate with a bearer token or a TLS client certificate, and
+// every credential request is passed to an Auditor. Changes are stored as
+// made by the caller's principal, for the store's Metadata policy.
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// The largest request body the server will read.
+const maxBodySize = 64 * 1024
+
+var xnameRE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Config holds the settings for a Server.
+type Config struct {
+	// The store the API reads and writes.
+	Store *cc.CompCredStore
+
+	// Tried in order until one identifies the caller. Requests that none
+	// of them identify are rejected.
+	Authenticators []Authenticator
+
+	// Receives an event for every credential request. Defaults to
+	// LogAuditor.
+	Auditor Auditor
+}
+
+// Server is an http.Handler serving the credential API.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+// Create a new Server.
+func NewServer(cfg Config) *Server {
+	if cfg.Auditor == nil {
+		cfg.Auditor = LogAuditor{}
+	}
+	s := &Server{
+		cfg: cfg,
+		mux: http.NewServeMux(),
+	}
+
+	s.handle("GET /v1/creds", ScopeRead, s.list)
+	s.handle("GET /v1/creds/{xname}", ScopeRead, s.get)
+	s.handle("PUT /v1/creds/{xname}", ScopeWrite, s.put)
+	s.handle("PATCH /v1/creds/{xname}", ScopeWrite, s.patch)
+	s.handle("DELETE /v1/creds/{xname}", ScopeDelete, s.delete)
+	s.mux.HandleFunc("GET /v1/schemas/{name}", s.schema)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// statusWriter remembers the status code written, for auditing.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, event *AuditEvent)
+
+// Register a credential route that requires a scope.
+func (s *Server) handle(pattern string, scope Scope, h handlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		event := &AuditEvent{
+			Time:       time.Now(),
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			Route:      pattern,
+			Xname:      r.PathValue("xname"),
+		}
+		defer func() {
+			event.Status = sw.status
+			s.cfg.Auditor.Audit(*event)
+		}()
+
+		p, err := s.authenticate(r)
+		if err != nil {
+			sw.Header().Set("WWW-Authenticate", `Bearer realm="compcreds"`)
+			writeProblem(sw, http.StatusUnauthorized, err.Error())
+			return
+		}
+		event.Principal = p.Name
+		if !p.HasScope(scope) {
+			writeProblem(sw, http.StatusForbidden, fmt.Sprintf("scope %s is required", scope))
+			return
+		}
+		if event.Xname != "" && !xnameRE.MatchString(event.Xname) {
+			writeProblem(sw, http.StatusBadRequest, "invalid xname")
+			return
+		}
+
+		h(sw, r, event)
+	})
+}
+
+func (s *Server) authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range s.cfg.Authenticators {
+		p, err := a.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("authentication required")
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, event *AuditEvent) {
+	creds, err := s.cfg.Store.GetAllCompCreds()
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "unable to read credentials")
+		return
+	}
+	xnames := make([]string, 0, len(creds))
+	for xname := range creds {
+		xnames = append(xnames, xname)
+	}
+	sort.Strings(xnames)
+	list := make([]cc.CompCredentials, 0, len(xnames))
+	for _, xname := range xnames {
+		list = append(list, creds[xname])
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Read a component's credentials, writing a problem response if they cannot
+// be read or do not exist.
+func (s *Server) lookup(w http.ResponseWriter, xname string) (cc.CompCredentials, bool) {
+	cred, err := s.cfg.Store.GetCompCred(xname)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "unable to read credentials")
+		return cred, false
+	}
+	if cred.Xname == "" {
+		writeProblem(w, http.StatusNotFound, "no credentials for "+xname)
+		return cred, false
+	}
+	return cred, true
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, event *AuditEvent) {
+	cred, ok := s.lookup(w, event.Xname)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, cred)
+}
+
+func (s *Server) put(w http.ResponseWriter, r *http.Request, event *AuditEvent) {
+	var cred cc.CompCredentials
+	if err := decodeBody(w, r, &cred); err != nil {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if cred.Xname == "" {
+		cred.Xname = event.Xname
+	}
+	if cred.Xname != event.Xname {
+		writeProblem(w, http.StatusBadRequest, "xname in body does not match path")
+		return
+	}
+
+	existing, err := s.cfg.Store.GetCompCred(event.Xname)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "unable to read credentials")
+		return
+	}
+	event.Fields = cc.ChangedFields(existing, cred)
+	// Only the store sets when and by whom credentials changed.
+	cred.Created, cred.Updated, cred.UpdatedBy = existing.Created, existing.Updated, existing.UpdatedBy
+
+	if err := s.cfg.Store.StoreCompCredAs(cred, event.Principal); err != nil {
+		writeStoreProblem(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// A partial update. Absent fields are left unchanged.
+type credPatch struct {
+	URL          *string `json:"url"`
+	Username     *string `json:"username"`
+	Password     *string `json:"password"`
+	SNMPAuthPass *string `json:"SNMPAuthPass"`
+	SNMPPrivPass *string `json:"SNMPPrivPass"`
+	SSHKey       *string `json:"SSHKey"`
+	SSHHostKey   *string `json:"SSHHostKey"`
+	Expires      *string `json:"expires"`
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, event *AuditEvent) {
+	var p credPatch
+	if err := decodeBody(w, r, &p); err != nil {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	existing, ok := s.lookup(w, event.Xname)
+	if !ok {
+		return
+	}
+	cred := existing
+	for _, f := range []struct {
+		from *string
+		to   *string
+	}{
+		{p.URL, &cred.URL},
+		{p.Username, &cred.Username},
+		{p.Password, &cred.Password},
+		{p.SNMPAuthPass, &cred.SNMPAuthPass},
+		{p.SNMPPrivPass, &cred.SNMPPrivPass},
+		{p.SSHKey, &cred.SSHKey},
+		{p.SSHHostKey, &cred.SSHHostKey},
+		{p.Expires, &cred.Expires},
+	} {
+		if f.from != nil {
+			*f.to = *f.from
+		}
+	}
+	event.Fields = cc.ChangedFields(existing, cred)
+
+	if err := s.cfg.Store.StoreCompCredAs(cred, event.Principal); err != nil {
+		writeStoreProblem(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Report a failure to store credentials. Refused passwords and invalid
+// expiry times are the client's to fix.
+func writeStoreProblem(w http.ResponseWriter, err error) {
+	if errors.Is(err, cc.ErrPasswordReused) {
+		writeProblem(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, cc.ErrInvalidExpiry) {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeProblem(w, http.StatusInternalServerError, "unable to store credentials")
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, event *AuditEvent) {
+	if err := s.cfg.Store.DeleteCompCred(event.Xname); err != nil {
+		writeProblem(w, http.StatusInternalServerError, "unable to delete credentials")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) schema(w http.ResponseWriter, r *http.Request) {
+	buf, err := schemaFS.ReadFile("schemas/" + r.PathValue("name"))
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "no such schema")
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(buf)
+}
+
+// Decode a single JSON object from the request body, rejecting unknown fields.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return fmt.Errorf("request body is too large")
+		}
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("invalid request body: unexpected data after object")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
@@ -0,0 +1,253 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+const (
+	readToken  = "read-token"
+	adminToken = "admin-token"
+)
+
+type testAuditor struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (ta *testAuditor) Audit(event AuditEvent) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	ta.events = append(ta.events, event)
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *cc.CompCredStore, *testAuditor) {
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	ccs.StoreCompCred(cc.CompCredentials{
+		Xname:    "x0c0s1b0",
+		URL:      "10.4.0.21/redfish/v1",
+		Username: "root",
+		Password: "123",
+	})
+	auditor := &testAuditor{}
+	s := NewServer(Config{
+		Store: ccs,
+		Authenticators: []Authenticator{
+			NewTokenAuthenticator(map[string]Principal{
+				readToken:  {Name: "reader", Scopes: []Scope{ScopeRead}},
+				adminToken: {Name: "admin", Scopes: []Scope{ScopeRead, ScopeWrite, ScopeDelete}},
+			}),
+		},
+		Auditor: auditor,
+	})
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return ts, ccs, auditor
+}
+
+func doRequest(t *testing.T, ts *httptest.Server, method, path, token, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unable to create request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp, string(respBody)
+}
+
+func TestRoutes(t *testing.T) {
+	ts, ccs, _ := newTestServer(t)
+
+	var tests = []struct {
+		method string
+		path   string
+		token  string
+		body   string
+		status int
+	}{
+		{"GET", "/v1/creds/x0c0s1b0", "", "", http.StatusUnauthorized},
+		{"GET", "/v1/creds/x0c0s1b0", "bogus", "", http.StatusUnauthorized},
+		{"GET", "/v1/creds/x0c0s1b0", readToken, "", http.StatusOK},
+		{"GET", "/v1/creds/x0c0s9b0", readToken, "", http.StatusNotFound},
+		{"GET", "/v1/creds/x0c0s1b0.bak", readToken, "", http.StatusBadRequest},
+		{"GET", "/v1/creds", readToken, "", http.StatusOK},
+		{"PUT", "/v1/creds/x0c0s2b0", readToken, `{"username":"admin"}`, http.StatusForbidden},
+		{"PUT", "/v1/creds/x0c0s2b0", adminToken, `{"username":"admin","password":"456"}`, http.StatusNoContent},
+		{"PUT", "/v1/creds/x0c0s2b0", adminToken, `{"xname":"x0c0s3b0"}`, http.StatusBadRequest},
+		{"PUT", "/v1/creds/x0c0s2b0", adminToken, `{"bogus":"field"}`, http.StatusBadRequest},
+		{"PUT", "/v1/creds/x0c0s2b0", adminToken, `{}{}`, http.StatusBadRequest},
+		{"PATCH", "/v1/creds/x0c0s1b0", adminToken, `{"password":"789"}`, http.StatusNoContent},
+		{"PATCH", "/v1/creds/x0c0s9b0", adminToken, `{"password":"789"}`, http.StatusNotFound},
+		{"DELETE", "/v1/creds/x0c0s2b0", readToken, "", http.StatusForbidden},
+		{"DELETE", "/v1/creds/x0c0s2b0", adminToken, "", http.StatusNoContent},
+		{"GET", "/v1/schemas/compcredentials.json", "", "", http.StatusOK},
+		{"GET", "/v1/schemas/bogus.json", "", "", http.StatusNotFound},
+	}
+
+	for i, test := range tests {
+		resp, body := doRequest(t, ts, test.method, test.path, test.token, test.body)
+		if resp.StatusCode != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, resp.StatusCode, body)
+		}
+	}
+
+	expected := cc.CompCredentials{
+		Xname:    "x0c0s1b0",
+		URL:      "10.4.0.21/redfish/v1",
+		Username: "root",
+		Password: "789",
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); !reflect.DeepEqual(cred, expected) {
+		t.Errorf("Expected PATCH to change only the password but got %#v", cred)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s2b0"); cred.Xname != "" {
+		t.Errorf("Expected x0c0s2b0 to be deleted")
+	}
+}
+
+func TestPasswordReused(t *testing.T) {
+	ts, ccs, _ := newTestServer(t)
+	ccs.PasswordReuse = &cc.ReusePolicy{Remember: 3, Key: []byte(strings.Repeat("k", cc.MinReuseKeyLength))}
+
+	var tests = []struct {
+		body   string
+		status int
+	}{
+		{`{"password":"789"}`, http.StatusNoContent},
+		{`{"password":"123"}`, http.StatusConflict},
+		{`{"url":"10.4.0.99/redfish/v1"}`, http.StatusNoContent},
+	}
+	for i, test := range tests {
+		resp, body := doRequest(t, ts, "PATCH", "/v1/creds/x0c0s1b0", adminToken, test.body)
+		if resp.StatusCode != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, resp.StatusCode, body)
+		}
+		if strings.Contains(body, "123") {
+			t.Errorf("Test %v Failed: Password in response %s", i, body)
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	ts, ccs, _ := newTestServer(t)
+	ccs.Metadata = &cc.MetadataPolicy{}
+
+	var tests = []struct {
+		method string
+		body   string
+		status int
+	}{
+		{"PUT", `{"username":"root","password":"789","updatedBy":"mallory"}`, http.StatusNoContent},
+		{"PATCH", `{"expires":"2030-01-01T00:00:00Z"}`, http.StatusNoContent},
+		{"PATCH", `{"expires":"soon"}`, http.StatusBadRequest},
+	}
+	for i, test := range tests {
+		resp, body := doRequest(t, ts, test.method, "/v1/creds/x0c0s1b0", adminToken, test.body)
+		if resp.StatusCode != test.status {
+			t.Errorf("Test %v Failed: Expected status %v but got %v: %s", i, test.status, resp.StatusCode, body)
+		}
+	}
+
+	cred, _ := ccs.GetCompCred("x0c0s1b0")
+	if cred.UpdatedBy != "admin" || cred.Updated == "" || cred.Expires != "2030-01-01T00:00:00Z" {
+		t.Errorf("Expected the change to be recorded for the caller but got %q %q %q", cred.Updated, cred.UpdatedBy, cred.Expires)
+	}
+}
+
+func TestGetBody(t *testing.T) {
+	ts, _, _ := newTestServer(t)
+
+	_, body := doRequest(t, ts, "GET", "/v1/creds", readToken, "")
+	var list []cc.CompCredentials
+	if err := json.Unmarshal([]byte(body), &list); err != nil {
+		t.Fatalf("Unable to parse response: %v", err)
+	}
+	if len(list) != 1 || list[0].Password != "123" {
+		t.Errorf("Expected the stored credentials but got %v", list)
+	}
+
+	resp, body := doRequest(t, ts, "GET", "/v1/creds/x0c0s9b0", readToken, "")
+	var p problem
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatalf("Unable to parse problem: %v", err)
+	}
+	if resp.Header.Get("Content-Type") != "application/problem+json" || p.Status != http.StatusNotFound {
+		t.Errorf("Expected a 404 problem but got %v: %s", resp.Header.Get("Content-Type"), body)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	ts, _, auditor := newTestServer(t)
+
+	doRequest(t, ts, "PATCH", "/v1/creds/x0c0s1b0", adminToken, `{"password":"secret-value"}`)
+	doRequest(t, ts, "GET", "/v1/creds/x0c0s1b0", "", "")
+	doRequest(t, ts, "GET", "/v1/schemas/problem.json", "", "")
+
+	if len(auditor.events) != 2 {
+		t.Fatalf("Expected 2 audit events but got %v", len(auditor.events))
+	}
+	var tests = []AuditEvent{
+		{
+			Principal: "admin",
+			Method:    "PATCH",
+			Route:     "PATCH /v1/creds/{xname}",
+			Xname:     "x0c0s1b0",
+			Status:    http.StatusNoContent,
+			Fields:    []string{"password"},
+		}, {
+			Method: "GET",
+			Route:  "GET /v1/creds/{xname}",
+			Xname:  "x0c0s1b0",
+			Status: http.StatusUnauthorized,
+		},
+	}
+	for i, test := range tests {
+		event := auditor.events[i]
+		event.Time = test.Time
+		event.RemoteAddr = test.RemoteAddr
+		if !reflect.DeepEqual(event, test) {
+			t.Errorf("Test %v Failed: Expected audit event %+v but got %+v", i, test, event)
+		}
+		if strings.Contains(strings.Join(event.Fields, ","), "secret-value") {
+			t.Errorf("Test %v Failed: Audit event reveals a secret", i)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The prefix of printable bundle key shares.
+const sharePrefix = "hmscc-share-v1"
+
+// The length of the random ID that ties together the shares of one key.
+const shareSetIDLen = 4
+
+var shareEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate a random bundle key and split it into n shares, any k of which
+// reconstruct it with CombineBundleShares (Shamir's secret sharing over
+// GF(256)). Fewer than k shares reveal nothing about the key. The shares
+// are printable, with a checksum to catch transcription errors.
+func SplitBundleKey(n, k int) (BundleKey, []string, error) {
+	if k < 2 || k > n || n > 255 {
+		return BundleKey{}, nil, fmt.Errorf("shares need 2 <= threshold <= count <= 255, not %d of %d", k, n)
+	}
+
+	key := make([]byte, 32)
+	setID := make([]byte, shareSetIDLen)
+	if _, err := rand.Read(key); err != nil {
+		return BundleKey{}, nil, err
+	}
+	if _, err := rand.Read(setID); err != nil {
+		return BundleKey{}, nil, err
+	}
+	ys, err := shamirSplit(key, n, k)
+	if err != nil {
+		return BundleKey{}, nil, err
+	}
+
+	shares := make([]string, n)
+	for i, y := range ys {
+		shares[i] = encodeShare(k, i+1, append(append([]byte(nil), setID...), y...))
+	}
+	return BundleKey{Key: key, threshold: k}, shares, nil
+}
+
+// Reconstruct a bundle key from shares made by SplitBundleKey. At least the
+// threshold number of shares of the same key must be given.
+func CombineBundleShares(shares []string) (BundleKey, error) {
+	if len(shares) == 0 {
+		return BundleKey{}, fmt.Errorf("no shares given")
+	}
+
+	var (
+		k     int
+		setID []byte
+		xs    []byte
+		ys    [][]byte
+	)
+	for i, s := range shares {
+		sk, x, payload, err := decodeShare(s)
+		if err != nil {
+			return BundleKey{}, fmt.Errorf("share %d: %v", i+1, err)
+		}
+		if i == 0 {
+			k, setID = sk, payload[:shareSetIDLen]
+		} else if sk != k || !bytes.Equal(payload[:shareSetIDLen], setID) {
+			return BundleKey{}, fmt.Errorf("share %d: not a share of the same key as share 1", i+1)
+		}
+		if bytes.IndexByte(xs, byte(x)) >= 0 {
+			return BundleKey{}, fmt.Errorf("share %d: share number %d given twice", i+1, x)
+		}
+		xs = append(xs, byte(x))
+		ys = append(ys, payload[shareSetIDLen:])
+	}
+	if len(xs) < k {
+		return BundleKey{}, fmt.Errorf("%d shares needed, only %d given", k, len(xs))
+	}
+
+	key, err := shamirCombine(xs[:k], ys[:k])
+	if err != nil {
+		return BundleKey{}, err
+	}
+	return BundleKey{Key: key, threshold: k}, nil
+}
+
+// Encode a share as "hmscc-share-v1-K-X-DATA", where DATA is the base32 of
+// the set ID, the share value and a checksum of everything before it.
+func encodeShare(k, x int, payload []byte) string {
+	head := fmt.Sprintf("%s-%d-%d", sharePrefix, k, x)
+	sum := sha256.Sum256(append([]byte(head), payload...))
+	return head + "-" + shareEncoding.EncodeToString(append(payload, sum[:4]...))
+}
+
+func decodeShare(s string) (k, x int, payload []byte, err error) {
+	s = strings.Join(strings.Fields(s), "")
+	if !strings.HasPrefix(s, sharePrefix+"-") {
+		return 0, 0, nil, fmt.Errorf("not a bundle key share")
+	}
+	parts := strings.Split(strings.TrimPrefix(s, sharePrefix+"-"), "-")
+	if len(parts) != 3 {
+		return 0, 0, nil, fmt.Errorf("malformed share")
+	}
+	if k, err = strconv.Atoi(parts[0]); err != nil || k < 2 || k > 255 {
+		return 0, 0, nil, fmt.Errorf("malformed share threshold")
+	}
+	if x, err = strconv.Atoi(parts[1]); err != nil || x < 1 || x > 255 {
+		return 0, 0, nil, fmt.Errorf("malformed share number")
+	}
+	data, err := shareEncoding.DecodeString(strings.ToUpper(parts[2]))
+	if err != nil || len(data) <= shareSetIDLen+4 {
+		return 0, 0, nil, fmt.Errorf("malformed share data")
+	}
+	payload, sum := data[:len(data)-4], data[len(data)-4:]
+	want := sha256.Sum256(append([]byte(fmt.Sprintf("%s-%d-%d", sharePrefix, k, x)), payload...))
+	if !bytes.Equal(sum, want[:4]) {
+		return 0, 0, nil, fmt.Errorf("checksum mismatch; check the share for typos")
+	}
+	return k, x, payload, nil
+}
+
+// Split a secret into n shares with threshold k. Share i is the value at
+// x = i+1 of a random polynomial of degree k-1, per byte, whose constant
+// term is the secret byte.
+func shamirSplit(secret []byte, n, k int) ([][]byte, error) {
+	ys := make([][]byte, n)
+	for i := range ys {
+		ys[i] = make([]byte, len(secret))
+	}
+	coeffs := make([]byte, k)
+	for b, s := range secret {
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+		coeffs[0] = s
+		for i := range ys {
+			// Horner's rule from the highest coefficient down.
+			x := byte(i + 1)
+			var y byte
+			for j := k - 1; j >= 0; j-- {
+				y = gfMul(y, x) ^ coeffs[j]
+			}
+			ys[i][b] = y
+		}
+	}
+	return ys, nil
+}
+
+// Recover the secret from shares by Lagrange interpolation at x = 0.
+func shamirCombine(xs []byte, ys [][]byte) ([]byte, error) {
+	secret := make([]byte, len(ys[0]))
+	for _, y := range ys {
+		if len(y) != len(secret) {
+			return nil, fmt.Errorf("shares are of different lengths")
+		}
+	}
+	for i, xi := range xs {
+		// The Lagrange basis polynomial for xi, evaluated at 0. In GF(256)
+		// subtraction is addition, so 0 - xj is xj.
+		basis := byte(1)
+		for j, xj := range xs {
+			if i != j {
+				basis = gfMul(basis, gfDiv(xj, xi^xj))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(ys[i][b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// Multiply in GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1,
+// without branching on the operands.
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		a = a<<1 ^ 0x1b&-(a>>7)
+		b >>= 1
+	}
+	return p
+}
+
+// Divide in GF(256); b must not be zero. The inverse of b is b^254.
+func gfDiv(a, b byte) byte {
+	inv := byte(1)
+	for i := 0; i < 254; i++ {
+		inv = gfMul(inv, b)
+	}
+	return gfMul(a, inv)
+}
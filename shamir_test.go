@@ -0,0 +1,110 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+func TestSplitBundleKey(t *testing.T) {
+	key, shares, err := cc.SplitBundleKey(5, 3)
+	if err != nil {
+		t.Fatalf("SplitBundleKey failed: %v", err)
+	}
+	if len(shares) != 5 || len(key.Key) != 32 {
+		t.Fatalf("Expected 5 shares of a 32 byte key but got %d of %d", len(shares), len(key.Key))
+	}
+	_, others, _ := cc.SplitBundleKey(5, 3)
+
+	// Flip one character of a share's data.
+	typo := []byte(shares[0])
+	if typo[len(typo)-3] == 'A' {
+		typo[len(typo)-3] = 'B'
+	} else {
+		typo[len(typo)-3] = 'A'
+	}
+
+	var tests = []struct {
+		shares []string
+		err    string
+	}{
+		{[]string{shares[0], shares[1], shares[2]}, ""},
+		{[]string{shares[4], shares[2], shares[0]}, ""},
+		{[]string{shares[1], shares[3], shares[4], shares[0]}, ""},
+		{[]string{"  " + strings.ToLower(shares[3]) + "\n", shares[1], shares[2]}, ""},
+		{[]string{shares[0], shares[1]}, "3 shares needed"},
+		{[]string{shares[0], shares[1], shares[1]}, "given twice"},
+		{[]string{shares[0], shares[1], others[2]}, "not a share of the same key"},
+		{[]string{string(typo), shares[1], shares[2]}, "checksum"},
+		{[]string{"correct horse", shares[1], shares[2]}, "not a bundle key share"},
+		{nil, "no shares"},
+	}
+
+	for i, test := range tests {
+		combined, err := cc.CombineBundleShares(test.shares)
+		if test.err == "" {
+			if err != nil || !bytes.Equal(combined.Key, key.Key) {
+				t.Errorf("Test %v Failed: Expected the key to be rebuilt but got %v", i, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Test %v Failed: Expected an error containing %q but got %v", i, test.err, err)
+		}
+	}
+
+	for _, nk := range [][2]int{{3, 1}, {2, 3}, {256, 2}} {
+		if _, _, err := cc.SplitBundleKey(nk[0], nk[1]); err == nil {
+			t.Errorf("Expected an error splitting into %d of %d", nk[1], nk[0])
+		}
+	}
+}
+
+func TestSplitKeyBundle(t *testing.T) {
+	key, shares, err := cc.SplitBundleKey(3, 2)
+	if err != nil {
+		t.Fatalf("SplitBundleKey failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := newBundleStore(t, bundleCreds...).Export(&buf, key); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if _, err := newBundleStore(t).Import(bytes.NewReader(buf.Bytes()), cc.BundleKey{Passphrase: "guess"}, cc.ImportOptions{}); err == nil || !strings.Contains(err.Error(), "2 are needed") {
+		t.Errorf("Expected an error saying 2 shares are needed but got %v", err)
+	}
+
+	combined, err := cc.CombineBundleShares(shares[1:])
+	if err != nil {
+		t.Fatalf("CombineBundleShares failed: %v", err)
+	}
+	ccs := newBundleStore(t)
+	if _, err := ccs.Import(&buf, combined, cc.ImportOptions{}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if cred, _ := ccs.GetCompCred(bundleCreds[2].Xname); cred != bundleCreds[2] {
+		t.Errorf("Expected %#v but got %#v", bundleCreds[2], cred)
+	}
+}
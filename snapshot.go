@@ -0,0 +1,329 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotInfo describes a point-in-time copy of a key space.
+type SnapshotInfo struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Note    string    `json:"note,omitempty"`
+	Count   int       `json:"count"`
+}
+
+// Snapshot is a point-in-time copy of every credential in a key space,
+// taken before a large operation so it can be undone.
+type Snapshot struct {
+	SnapshotInfo
+	Credentials map[string]CompCredentials `json:"credentials"`
+}
+
+// CredentialDiff describes how one component's credentials differ between
+// two sets. Change is "added" or "removed" if only the second or the first
+// set has them, or "changed", with Fields listing the JSON names of the
+// fields that differ, never their values.
+type CredentialDiff struct {
+	Xname  string   `json:"xname"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// The form snapshot details are stored in. Times are RFC 3339 strings so
+// every SecureStorage can hold them.
+type snapshotRecord struct {
+	Name    string
+	Created string
+	Note    string
+	Count   int
+}
+
+// Get the key space snapshots of this one are stored in. Each snapshot is
+// stored under its name, as an "info" record and the credentials under
+// "creds".
+func (ccs *CompCredStore) snapshotPath() string {
+	return ccs.CCPath + "-snapshots"
+}
+
+// Copy every stored credential into a Snapshot in memory, such as to write
+// to a file with WriteSnapshot.
+func (ccs *CompCredStore) CurrentSnapshot(name, note string) (Snapshot, error) {
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	delete(creds, "")
+	return Snapshot{
+		SnapshotInfo: SnapshotInfo{Name: name, Created: time.Now().UTC(), Note: note, Count: len(creds)},
+		Credentials:  creds,
+	}, nil
+}
+
+// Copy every stored credential to a snapshot. The name defaults to the
+// current time, such as "20261018T143000Z", and must not already be used.
+func (ccs *CompCredStore) TakeSnapshot(name, note string) (SnapshotInfo, error) {
+	if name == "" {
+		name = time.Now().UTC().Format("20060102T150405Z")
+	}
+	if strings.Contains(name, "/") {
+		return SnapshotInfo{}, fmt.Errorf("snapshot names cannot contain '/'")
+	}
+	var existing snapshotRecord
+	if err := ccs.SS.Lookup(ccs.snapshotPath()+"/"+name+"/info", &existing); err != nil {
+		return SnapshotInfo{}, err
+	}
+	if existing.Name != "" {
+		return SnapshotInfo{}, fmt.Errorf("snapshot %s already exists", name)
+	}
+
+	s, err := ccs.CurrentSnapshot(name, note)
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+	for _, cred := range sortedCreds(s.Credentials) {
+		if err := ccs.SS.Store(ccs.snapshotPath()+"/"+name+"/creds/"+cred.Xname, cred); err != nil {
+			return SnapshotInfo{}, fmt.Errorf("unable to store snapshot of %s: %v", cred.Xname, err)
+		}
+	}
+
+	// The info record is written last, so only complete snapshots are
+	// listed.
+	record := snapshotRecord{Name: name, Created: s.Created.Format(time.RFC3339Nano), Note: note, Count: s.Count}
+	if err := ccs.SS.Store(ccs.snapshotPath()+"/"+name+"/info", record); err != nil {
+		return SnapshotInfo{}, err
+	}
+	return s.SnapshotInfo, nil
+}
+
+// List the snapshots of this key space, oldest first.
+func (ccs *CompCredStore) ListSnapshots() ([]SnapshotInfo, error) {
+	names, err := LookupKeys(ccs.SS, ccs.snapshotPath())
+	if err != nil {
+		return nil, err
+	}
+	var infos []SnapshotInfo
+	for _, name := range names {
+		var record snapshotRecord
+		if err := ccs.SS.Lookup(ccs.snapshotPath()+"/"+strings.TrimSuffix(name, "/")+"/info", &record); err != nil {
+			return nil, err
+		}
+		if record.Name == "" {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339, record.Created)
+		infos = append(infos, SnapshotInfo{Name: record.Name, Created: created, Note: record.Note, Count: record.Count})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].Created.Equal(infos[j].Created) {
+			return infos[i].Created.Before(infos[j].Created)
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
+
+// Get a snapshot of this key space by name.
+func (ccs *CompCredStore) GetSnapshot(name string) (Snapshot, error) {
+	var record snapshotRecord
+	if err := ccs.SS.Lookup(ccs.snapshotPath()+"/"+name+"/info", &record); err != nil {
+		return Snapshot{}, err
+	}
+	if record.Name == "" {
+		return Snapshot{}, fmt.Errorf("no snapshot named %s", name)
+	}
+	created, _ := time.Parse(time.RFC3339, record.Created)
+	s := Snapshot{
+		SnapshotInfo: SnapshotInfo{Name: record.Name, Created: created, Note: record.Note, Count: record.Count},
+		Credentials:  make(map[string]CompCredentials, record.Count),
+	}
+
+	path := ccs.snapshotPath() + "/" + name + "/creds"
+	xnames, err := LookupKeys(ccs.SS, path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, xname := range xnames {
+		var cred CompCredentials
+		if err := ccs.SS.Lookup(path+"/"+xname, &cred); err != nil {
+			return Snapshot{}, fmt.Errorf("unable to get snapshot of %s: %v", xname, err)
+		}
+		if cred.Xname != "" {
+			s.Credentials[cred.Xname] = cred
+		}
+	}
+	if len(s.Credentials) != record.Count {
+		return Snapshot{}, fmt.Errorf("snapshot %s should hold %d credentials but has %d", name, record.Count, len(s.Credentials))
+	}
+	return s, nil
+}
+
+// Remove a snapshot of this key space. Removing a snapshot that does not
+// exist is not an error.
+func (ccs *CompCredStore) DeleteSnapshot(name string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	// The info record goes first, so a partly deleted snapshot is not
+	// listed.
+	if err := ccs.SS.Delete(ccs.snapshotPath() + "/" + name + "/info"); err != nil {
+		return err
+	}
+	path := ccs.snapshotPath() + "/" + name + "/creds"
+	xnames, err := LookupKeys(ccs.SS, path)
+	if err != nil {
+		return err
+	}
+	for _, xname := range xnames {
+		if err := ccs.SS.Delete(path + "/" + xname); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compare a snapshot with the stored credentials. "added" are stored but
+// not in the snapshot, and "removed" are in the snapshot but no longer
+// stored.
+func (ccs *CompCredStore) DiffSnapshot(s Snapshot) ([]CredentialDiff, error) {
+	creds, err := ccs.GetAllCompCreds()
+	if err != nil {
+		return nil, err
+	}
+	delete(creds, "")
+	return diffCreds(s.Credentials, creds), nil
+}
+
+// Store the credentials in a snapshot, or only those of the given xnames,
+// and return what was, or with dryRun would be, done with each: "created",
+// "overwritten" or "unchanged", as for Import. Credentials stored since the
+// snapshot was taken are left alone, and restored passwords are not refused
+// as password reuse.
+func (ccs *CompCredStore) RestoreSnapshot(s Snapshot, xnames []string, dryRun bool) ([]ImportChange, error) {
+	if len(xnames) == 0 {
+		for xname := range s.Credentials {
+			xnames = append(xnames, xname)
+		}
+	}
+	xnames = append([]string(nil), xnames...)
+	sort.Strings(xnames)
+	for _, xname := range xnames {
+		if _, ok := s.Credentials[xname]; !ok {
+			return nil, fmt.Errorf("snapshot %s has no credentials for %s", s.Name, xname)
+		}
+	}
+
+	changes := make([]ImportChange, 0, len(xnames))
+	for _, xname := range xnames {
+		existing, err := ccs.GetCompCred(xname)
+		if err != nil {
+			return changes, fmt.Errorf("unable to get credentials for %s: %v", xname, err)
+		}
+		change := ImportChange{Xname: xname, Change: "created"}
+		if existing.Xname != "" {
+			change.Fields = ChangedFields(existing, s.Credentials[xname])
+			change.Change = "overwritten"
+			if len(change.Fields) == 0 {
+				change.Change = "unchanged"
+			}
+		}
+		changes = append(changes, change)
+		if dryRun || change.Change == "unchanged" {
+			continue
+		}
+		if err := ccs.storeCompCred(s.Credentials[xname], "", storeRestored); err != nil {
+			return changes, fmt.Errorf("unable to store credentials for %s: %v", xname, err)
+		}
+	}
+	return changes, nil
+}
+
+// Write a snapshot to w as a bundle, encrypted as by Export.
+func WriteSnapshot(w io.Writer, s Snapshot, key BundleKey) error {
+	payload := bundlePayload{
+		Credentials: sortedCreds(s.Credentials),
+		Snapshot:    s.Name,
+		Note:        s.Note,
+	}
+	created := s.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	return sealBundle(w, key, created, payload)
+}
+
+// Read a snapshot written by WriteSnapshot. A bundle written by Export is
+// read as an unnamed snapshot.
+func ReadSnapshot(r io.Reader, key BundleKey) (Snapshot, error) {
+	header, payload, err := openBundle(r, key)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	created, _ := time.Parse(time.RFC3339, header.Created)
+	s := Snapshot{
+		SnapshotInfo: SnapshotInfo{Name: payload.Snapshot, Created: created, Note: payload.Note, Count: len(payload.Credentials)},
+		Credentials:  make(map[string]CompCredentials, len(payload.Credentials)),
+	}
+	for _, cred := range payload.Credentials {
+		s.Credentials[cred.Xname] = cred
+	}
+	return s, nil
+}
+
+// Compare two sets of credentials, ordered by xname.
+func diffCreds(from, to map[string]CompCredentials) []CredentialDiff {
+	xnames := make(map[string]bool, len(from)+len(to))
+	for xname := range from {
+		xnames[xname] = true
+	}
+	for xname := range to {
+		xnames[xname] = true
+	}
+	sorted := make([]string, 0, len(xnames))
+	for xname := range xnames {
+		sorted = append(sorted, xname)
+	}
+	sort.Strings(sorted)
+
+	var diffs []CredentialDiff
+	for _, xname := range sorted {
+		a, inFrom := from[xname]
+		b, inTo := to[xname]
+		switch {
+		case !inFrom:
+			diffs = append(diffs, CredentialDiff{Xname: xname, Change: "added"})
+		case !inTo:
+			diffs = append(diffs, CredentialDiff{Xname: xname, Change: "removed"})
+		default:
+			if fields := ChangedFields(a, b); len(fields) > 0 {
+				diffs = append(diffs, CredentialDiff{Xname: xname, Change: "changed", Fields: fields})
+			}
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,173 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Snapshot the bundle credentials, then rotate the first, remove the
+// second and add a fourth.
+func snapshotAndChange(t *testing.T, ccs *cc.CompCredStore) cc.SnapshotInfo {
+	info, err := ccs.TakeSnapshot("before-rotation", "firmware campaign")
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	rotated := bundleCreds[0]
+	rotated.Password = "rotated"
+	ccs.StoreCompCred(rotated)
+	ccs.DeleteCompCred(bundleCreds[1].Xname)
+	ccs.StoreCompCred(cc.CompCredentials{Xname: "x0c0s4b0", Username: "root", Password: "new"})
+	return info
+}
+
+func TestSnapshots(t *testing.T) {
+	ccs := newBundleStore(t, bundleCreds...)
+	info := snapshotAndChange(t, ccs)
+	if info.Name != "before-rotation" || info.Count != 3 || info.Created.IsZero() {
+		t.Errorf("Unexpected snapshot info %+v", info)
+	}
+	if _, err := ccs.TakeSnapshot("before-rotation", ""); err == nil {
+		t.Errorf("Expected an error reusing a snapshot name")
+	}
+	if _, err := ccs.TakeSnapshot("a/b", ""); err == nil {
+		t.Errorf("Expected an error for a name with a slash")
+	}
+	auto, err := ccs.TakeSnapshot("", "")
+	if err != nil || auto.Name == "" || auto.Count != 3 {
+		t.Errorf("Expected a snapshot named by time but got %+v, %v", auto, err)
+	}
+
+	// Snapshots are not credentials.
+	all, _ := ccs.GetAllCompCreds()
+	if len(all) != 3 {
+		t.Errorf("Expected 3 stored credentials but got %v", len(all))
+	}
+
+	infos, err := ccs.ListSnapshots()
+	if err != nil || len(infos) != 2 || infos[0].Name != "before-rotation" || infos[0].Note != "firmware campaign" {
+		t.Fatalf("Expected two snapshots, oldest first, but got %+v, %v", infos, err)
+	}
+
+	s, err := ccs.GetSnapshot("before-rotation")
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if len(s.Credentials) != 3 || !reflect.DeepEqual(s.Credentials[bundleCreds[1].Xname], bundleCreds[1]) {
+		t.Errorf("Expected the snapshot to hold the original credentials but got %v", s.Credentials)
+	}
+	if _, err := ccs.GetSnapshot("missing"); err == nil {
+		t.Errorf("Expected an error getting a missing snapshot")
+	}
+
+	diffs, err := ccs.DiffSnapshot(s)
+	expected := []cc.CredentialDiff{
+		{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"password"}},
+		{Xname: "x0c0s2b0", Change: "removed"},
+		{Xname: "x0c0s4b0", Change: "added"},
+	}
+	if err != nil || !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("Expected diff %v but got %v, %v", expected, diffs, err)
+	}
+
+	var tests = []struct {
+		xnames  []string
+		dryRun  bool
+		changes []string
+		err     bool
+	}{
+		{[]string{"x0c0s4b0"}, false, nil, true},
+		{nil, true, []string{"overwritten", "created", "unchanged"}, false},
+		{[]string{"x0c0s2b0"}, false, []string{"created"}, false},
+		{nil, false, []string{"overwritten", "unchanged", "unchanged"}, false},
+	}
+	for i, test := range tests {
+		changes, err := ccs.RestoreSnapshot(s, test.xnames, test.dryRun)
+		if (err != nil) != test.err {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.err, err)
+		}
+		var got []string
+		for _, change := range changes {
+			got = append(got, change.Change)
+		}
+		if !reflect.DeepEqual(got, test.changes) {
+			t.Errorf("Test %v Failed: Expected changes %v but got %v", i, test.changes, got)
+		}
+	}
+	for _, cred := range bundleCreds {
+		if stored, _ := ccs.GetCompCred(cred.Xname); stored != cred {
+			t.Errorf("Expected %s to be restored but got %#v", cred.Xname, stored)
+		}
+	}
+	if stored, _ := ccs.GetCompCred("x0c0s4b0"); stored.Xname == "" {
+		t.Errorf("Expected credentials added after the snapshot to be kept")
+	}
+
+	if err := ccs.DeleteSnapshot("before-rotation"); err != nil {
+		t.Fatalf("DeleteSnapshot failed: %v", err)
+	}
+	if infos, _ := ccs.ListSnapshots(); len(infos) != 1 || infos[0].Name != auto.Name {
+		t.Errorf("Expected only %s to remain but got %+v", auto.Name, infos)
+	}
+	if err := ccs.DeleteSnapshot("before-rotation"); err != nil {
+		t.Errorf("Expected deleting a missing snapshot to succeed but got %v", err)
+	}
+}
+
+func TestSnapshotFile(t *testing.T) {
+	key := cc.BundleKey{Passphrase: "correct horse"}
+	ccs := newBundleStore(t, bundleCreds...)
+	snapshotAndChange(t, ccs)
+	s, _ := ccs.GetSnapshot("before-rotation")
+
+	var buf bytes.Buffer
+	if err := cc.WriteSnapshot(&buf, s, key); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "secret-one") {
+		t.Errorf("Expected the snapshot file not to contain secrets in the clear")
+	}
+	read, err := cc.ReadSnapshot(&buf, key)
+	if err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+	// Bundles record times to the second.
+	s.Created = s.Created.Truncate(time.Second)
+	if !reflect.DeepEqual(read, s) {
+		t.Errorf("Expected %+v but got %+v", s, read)
+	}
+
+	// An export reads as an unnamed snapshot of the live credentials.
+	buf.Reset()
+	ccs.Export(&buf, key)
+	read, err = cc.ReadSnapshot(&buf, key)
+	if err != nil || read.Name != "" || read.Count != 3 || read.Credentials["x0c0s4b0"].Password != "new" {
+		t.Errorf("Expected an export to read as a snapshot but got %+v, %v", read.SnapshotInfo, err)
+	}
+}
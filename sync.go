@@ -0,0 +1,136 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials
+
+import (
+	"fmt"
+)
+
+// SyncOptions control Sync.
+type SyncOptions struct {
+	// Only sync these xnames. Defaults to all of them.
+	Xnames []string
+
+	// Remove credentials the source does not have from the destination.
+	// Without it they are reported as "skipped".
+	Delete bool
+
+	// Report the changes without making them.
+	DryRun bool
+}
+
+// SyncResult describes what Sync did, or would do, with one component's
+// credentials. Change is one of "created", "updated", "deleted" or
+// "skipped"; Fields lists the JSON names of the fields that differ, never
+// their values; and Error is set if the change failed.
+type SyncResult struct {
+	Xname  string   `json:"xname"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Compare the credentials in two stores, such as two Vault instances or two
+// key spaces, ordered by xname. "added" are only in b, "removed" only in a,
+// and "changed" in both but different.
+func Diff(a, b *CompCredStore) ([]CredentialDiff, error) {
+	aCreds, err := a.GetAllCompCreds()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list credentials in %s: %v", a.CCPath, err)
+	}
+	bCreds, err := b.GetAllCompCreds()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list credentials in %s: %v", b.CCPath, err)
+	}
+	delete(aCreds, "")
+	delete(bCreds, "")
+	return diffCreds(aCreds, bCreds), nil
+}
+
+// Make the credentials in dst match those in src, and return what was, or
+// with DryRun would be, done with each xname that differs. A failure to
+// store or delete one xname does not stop the others; its result has Error
+// set, and an error is returned after all have been tried.
+func Sync(src, dst *CompCredStore, opts SyncOptions) ([]SyncResult, error) {
+	diffs, err := Diff(dst, src)
+	if err != nil {
+		return nil, err
+	}
+	var only map[string]bool
+	if len(opts.Xnames) > 0 {
+		only = make(map[string]bool, len(opts.Xnames))
+		for _, xname := range opts.Xnames {
+			only[xname] = true
+		}
+	}
+
+	var (
+		results []SyncResult
+		failed  []string
+	)
+	for _, d := range diffs {
+		if only != nil && !only[d.Xname] {
+			continue
+		}
+		res := SyncResult{Xname: d.Xname, Fields: d.Fields}
+		var apply func() error
+		switch d.Change {
+		case "added":
+			res.Change = "created"
+			apply = func() error { return copyCred(src, dst, d.Xname) }
+		case "changed":
+			res.Change = "updated"
+			apply = func() error { return copyCred(src, dst, d.Xname) }
+		case "removed":
+			res.Change = "skipped"
+			if opts.Delete {
+				res.Change = "deleted"
+				apply = func() error { return dst.DeleteCompCred(d.Xname) }
+			}
+		}
+		if apply != nil && !opts.DryRun {
+			if err := apply(); err != nil {
+				res.Error = err.Error()
+				failed = append(failed, d.Xname)
+			}
+		}
+		results = append(results, res)
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("unable to sync %d of %d credentials", len(failed), len(results))
+	}
+	return results, nil
+}
+
+// Copy one component's credentials between stores, with their metadata,
+// reading them again in case they changed since they were compared.
+func copyCred(src, dst *CompCredStore, xname string) error {
+	cred, err := src.GetCompCred(xname)
+	if err != nil {
+		return err
+	}
+	if cred.Xname == "" {
+		return fmt.Errorf("no longer stored in the source")
+	}
+	return dst.storeCompCred(cred, "", storeCopied)
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+)
+
+// failingStorage refuses to store keys containing fail.
+type failingStorage struct {
+	*conformance.MemoryStorage
+	fail string
+}
+
+func (fs *failingStorage) Store(key string, value interface{}) error {
+	if fs.fail != "" && strings.Contains(key, fs.fail) {
+		return fmt.Errorf("storage unavailable")
+	}
+	return fs.MemoryStorage.Store(key, value)
+}
+
+// Two stores: b has the first credentials changed, not the second, and an
+// extra fourth.
+func diffStores(t *testing.T) (*cc.CompCredStore, *cc.CompCredStore, *failingStorage) {
+	a := newBundleStore(t, bundleCreds...)
+	fs := &failingStorage{MemoryStorage: conformance.NewMemoryStorage()}
+	b := cc.NewCompCredStore("hms-creds-new", fs)
+	changed := bundleCreds[0]
+	changed.URL = "10.4.0.99"
+	changed.Password = "rotated"
+	for _, cred := range []cc.CompCredentials{changed, bundleCreds[2], {Xname: "x0c0s4b0", Password: "extra"}} {
+		b.StoreCompCred(cred)
+	}
+	return a, b, fs
+}
+
+func TestDiff(t *testing.T) {
+	a, b, _ := diffStores(t)
+
+	diffs, err := cc.Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := []cc.CredentialDiff{
+		{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"url", "password"}},
+		{Xname: "x0c0s2b0", Change: "removed"},
+		{Xname: "x0c0s4b0", Change: "added"},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("Expected %v but got %v", expected, diffs)
+	}
+	if diffs, _ := cc.Diff(a, a); len(diffs) != 0 {
+		t.Errorf("Expected a store not to differ from itself but got %v", diffs)
+	}
+}
+
+func TestSync(t *testing.T) {
+	var tests = []struct {
+		opts    cc.SyncOptions
+		fail    string
+		results []cc.SyncResult
+		after   []cc.CredentialDiff
+		err     bool
+	}{
+		{
+			opts: cc.SyncOptions{DryRun: true, Delete: true},
+			results: []cc.SyncResult{
+				{Xname: "x0c0s1b0", Change: "updated", Fields: []string{"url", "password"}},
+				{Xname: "x0c0s2b0", Change: "created"},
+				{Xname: "x0c0s4b0", Change: "deleted"},
+			},
+			after: []cc.CredentialDiff{
+				{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"url", "password"}},
+				{Xname: "x0c0s2b0", Change: "removed"},
+				{Xname: "x0c0s4b0", Change: "added"},
+			},
+		}, {
+			opts: cc.SyncOptions{},
+			results: []cc.SyncResult{
+				{Xname: "x0c0s1b0", Change: "updated", Fields: []string{"url", "password"}},
+				{Xname: "x0c0s2b0", Change: "created"},
+				{Xname: "x0c0s4b0", Change: "skipped"},
+			},
+			after: []cc.CredentialDiff{{Xname: "x0c0s4b0", Change: "added"}},
+		}, {
+			opts:    cc.SyncOptions{Delete: true, Xnames: []string{"x0c0s2b0", "x0c0s4b0"}},
+			results: []cc.SyncResult{{Xname: "x0c0s2b0", Change: "created"}, {Xname: "x0c0s4b0", Change: "deleted"}},
+			after:   []cc.CredentialDiff{{Xname: "x0c0s1b0", Change: "changed", Fields: []string{"url", "password"}}},
+		}, {
+			opts: cc.SyncOptions{Delete: true},
+			fail: "x0c0s2b0",
+			results: []cc.SyncResult{
+				{Xname: "x0c0s1b0", Change: "updated", Fields: []string{"url", "password"}},
+				{Xname: "x0c0s2b0", Change: "created", Error: "storage unavailable"},
+				{Xname: "x0c0s4b0", Change: "deleted"},
+			},
+			after: []cc.CredentialDiff{{Xname: "x0c0s2b0", Change: "removed"}},
+			err:   true,
+		},
+	}
+
+	for i, test := range tests {
+		a, b, fs := diffStores(t)
+		fs.fail = test.fail
+		results, err := cc.Sync(a, b, test.opts)
+		if (err != nil) != test.err {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.err, err)
+		}
+		if !reflect.DeepEqual(results, test.results) {
+			t.Errorf("Test %v Failed: Expected results %v but got %v", i, test.results, results)
+		}
+		if after, _ := cc.Diff(a, b); !reflect.DeepEqual(after, test.after) {
+			t.Errorf("Test %v Failed: Expected the stores to then differ by %v but got %v", i, test.after, after)
+		}
+	}
+}
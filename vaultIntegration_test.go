@@ -0,0 +1,269 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package compcredentials_test
+
+import (
+	"reflect"
+	"testing"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+	"github.com/Cray-HPE/hms-compcredentials/vaulttest"
+)
+
+// These tests drive CompCredStore through the real VaultAdapter against the
+// fake Vault server in vaulttest.
+
+func newVaultStore(t *testing.T) (*cc.CompCredStore, *vaulttest.Server) {
+	s := vaulttest.NewServer()
+	t.Cleanup(s.Close)
+	ss := s.NewVaultAdapter(t, "secret")
+	return cc.NewCompCredStore(cc.DefaultCompCredPath, ss), s
+}
+
+func TestVaultAdapterConformance(t *testing.T) {
+	conformance.Suite{
+		New: func(t *testing.T) sstorage.SecureStorage {
+			s := vaulttest.NewServer()
+			t.Cleanup(s.Close)
+			return s.NewVaultAdapter(t, "secret")
+		},
+		Skip: map[string]string{
+			"LookupKeysMissingPath": "VaultAdapter.LookupKeys dereferences the nil secret Vault returns for an empty path",
+		},
+	}.Run(t)
+}
+
+func TestVaultCompCredStore(t *testing.T) {
+	ccs, s := newVaultStore(t)
+
+	creds := map[string]cc.CompCredentials{
+		"x0c0s1b0": {
+			Xname:        "x0c0s1b0",
+			URL:          "10.4.0.21/redfish/v1/UpdateService",
+			Username:     "test1",
+			Password:     "123",
+			SNMPAuthPass: "auth1",
+			SNMPPrivPass: "priv1",
+		},
+		"x0c0s2b0": {
+			Xname:    "x0c0s2b0",
+			URL:      "10.4.0.22/redfish/v1/UpdateService",
+			Username: "test2",
+			Password: "456",
+		},
+	}
+	for _, cred := range creds {
+		if err := ccs.StoreCompCred(cred); err != nil {
+			t.Fatalf("StoreCompCred(%s) failed: %v", cred.Xname, err)
+		}
+	}
+
+	// The VaultAdapter writes struct field names, not JSON tags, to Vault.
+	data, ok := s.Data("secret/hms-creds/x0c0s1b0")
+	if !ok {
+		t.Fatalf("Expected x0c0s1b0 to be stored in Vault")
+	}
+	if data["Username"] != "test1" || data["Xname"] != "x0c0s1b0" {
+		t.Errorf("Unexpected Vault data layout: keys %v", reflect.ValueOf(data).MapKeys())
+	}
+
+	cred, err := ccs.GetCompCred("x0c0s1b0")
+	if err != nil {
+		t.Fatalf("GetCompCred failed: %v", err)
+	}
+	if !reflect.DeepEqual(cred, creds["x0c0s1b0"]) {
+		t.Errorf("Expected GetCompCred to return the stored credentials")
+	}
+
+	all, err := ccs.GetAllCompCreds()
+	if err != nil {
+		t.Fatalf("GetAllCompCreds failed: %v", err)
+	}
+	if !reflect.DeepEqual(all, creds) {
+		t.Errorf("Expected GetAllCompCreds to return %v but got %v", creds, all)
+	}
+
+	some, err := ccs.GetCompCreds([]string{"x0c0s2b0"})
+	if err != nil {
+		t.Fatalf("GetCompCreds failed: %v", err)
+	}
+	if len(some) != 1 || !reflect.DeepEqual(some["x0c0s2b0"], creds["x0c0s2b0"]) {
+		t.Errorf("Expected only x0c0s2b0 from GetCompCreds but got %v", some)
+	}
+}
+
+func TestVaultTokenRefresh(t *testing.T) {
+	ccs, s := newVaultStore(t)
+	logins := s.Logins()
+
+	cred := cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "123"}
+	s.RevokeTokens()
+	if err := ccs.StoreCompCred(cred); err != nil {
+		t.Fatalf("Expected StoreCompCred to log in again after token revocation but got: %v", err)
+	}
+	if s.Logins() != logins+1 {
+		t.Errorf("Expected one new login but got %v", s.Logins()-logins)
+	}
+
+	s.RevokeTokens()
+	got, err := ccs.GetCompCred(cred.Xname)
+	if err != nil {
+		t.Fatalf("Expected GetCompCred to log in again after token revocation but got: %v", err)
+	}
+	if !reflect.DeepEqual(got, cred) {
+		t.Errorf("Expected %v but got %v", cred, got)
+	}
+}
+
+func TestVaultSnapshots(t *testing.T) {
+	ccs, _ := newVaultStore(t)
+
+	// Listing snapshots of a key space with none must not hit the
+	// VaultAdapter's empty path panic.
+	if infos, err := ccs.ListSnapshots(); err != nil || len(infos) != 0 {
+		t.Fatalf("Expected no snapshots but got %v, %v", infos, err)
+	}
+
+	cred := cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "123"}
+	ccs.StoreCompCred(cred)
+	if _, err := ccs.TakeSnapshot("before", "note"); err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	ccs.DeleteCompCred(cred.Xname)
+
+	infos, err := ccs.ListSnapshots()
+	if err != nil || len(infos) != 1 || infos[0].Count != 1 || infos[0].Created.IsZero() {
+		t.Fatalf("Expected one snapshot but got %+v, %v", infos, err)
+	}
+	s, err := ccs.GetSnapshot("before")
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if _, err := ccs.RestoreSnapshot(s, nil, false); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+	if got, _ := ccs.GetCompCred(cred.Xname); !reflect.DeepEqual(got, cred) {
+		t.Errorf("Expected %v to be restored but got %v", cred, got)
+	}
+}
+
+func TestVaultFallback(t *testing.T) {
+	ccs, _ := newVaultStore(t)
+	cred := cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "123"}
+	ccs.StoreCompCred(cred)
+
+	// The new key space has no keys yet, which must not hit the
+	// VaultAdapter's empty path panic.
+	v2 := cc.NewCompCredStore("hms-creds-v2", ccs.SS)
+	v2.FallbackPaths = []string{ccs.CCPath}
+	v2.MigrateOnRead = true
+	if creds, err := v2.GetAllCompCreds(); err != nil || !reflect.DeepEqual(creds["x0c0s1b0"], cred) {
+		t.Fatalf("Expected %v from the fallback path but got %v, %v", cred, creds, err)
+	}
+	v2.FallbackPaths = nil
+	if got, _ := v2.GetCompCred(cred.Xname); !reflect.DeepEqual(got, cred) {
+		t.Errorf("Expected %v to be migrated but got %v", cred, got)
+	}
+}
+
+func TestVaultSync(t *testing.T) {
+	src, _ := newVaultStore(t)
+	cred := cc.CompCredentials{Xname: "x0c0s1b0", Username: "root", Password: "123"}
+	src.StoreCompCred(cred)
+
+	// Syncing exists to fill a fresh Vault, whose empty key space must not
+	// hit the VaultAdapter's empty path panic.
+	dst, _ := newVaultStore(t)
+	if all, err := dst.GetAllCompCreds(); err != nil || len(all) != 0 {
+		t.Fatalf("Expected no credentials in the new Vault but got %v, %v", all, err)
+	}
+	diffs, err := cc.Diff(src, dst)
+	if err != nil || !reflect.DeepEqual(diffs, []cc.CredentialDiff{{Xname: "x0c0s1b0", Change: "removed"}}) {
+		t.Fatalf("Expected x0c0s1b0 to be missing from the new Vault but got %v, %v", diffs, err)
+	}
+	results, err := cc.Sync(src, dst, cc.SyncOptions{})
+	if err != nil || !reflect.DeepEqual(results, []cc.SyncResult{{Xname: "x0c0s1b0", Change: "created"}}) {
+		t.Fatalf("Expected x0c0s1b0 to be created but got %v, %v", results, err)
+	}
+	if got, _ := dst.GetCompCred(cred.Xname); !reflect.DeepEqual(got, cred) {
+		t.Errorf("Expected %v in the new Vault but got %v", cred, got)
+	}
+}
+
+func newKV2Adapter(t *testing.T, s *vaulttest.Server) *cc.KV2Adapter {
+	s.Mount("kv", 2)
+	return cc.NewKV2Adapter(s.NewVaultAdapter(t, "kv").(*sstorage.VaultAdapter))
+}
+
+func TestKV2AdapterConformance(t *testing.T) {
+	conformance.RunSuite(t, func(t *testing.T) sstorage.SecureStorage {
+		s := vaulttest.NewServer()
+		t.Cleanup(s.Close)
+		return newKV2Adapter(t, s)
+	})
+}
+
+func TestKV2History(t *testing.T) {
+	s := vaulttest.NewServer()
+	t.Cleanup(s.Close)
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, newKV2Adapter(t, s))
+	s.SetMaxVersions("kv", 3)
+	// The backend keeps the history, not the store.
+	ccs.HistoryLimit = 10
+	storePasswords(t, ccs, "one", "two", "three", "four")
+
+	versions, err := ccs.ListCompCredVersions("x0c0s1b0")
+	if err != nil || !reflect.DeepEqual(versionNumbers(versions), []int{2, 3, 4}) || versions[0].Created.IsZero() {
+		t.Fatalf("Expected versions 2 to 4 but got %v, %v", versions, err)
+	}
+	if _, ok := s.Data("kv/hms-creds-history/x0c0s1b0/index"); ok {
+		t.Errorf("Expected no emulated history on a versioned backend")
+	}
+
+	// Reading a version logs in again after token revocation.
+	s.RevokeTokens()
+	if cred, err := ccs.GetCompCredVersion("x0c0s1b0", 2); err != nil || cred.Password != "two" {
+		t.Errorf("Expected version 2 but got %v, %v", cred, err)
+	}
+	if cred, err := ccs.GetCompCredVersion("x0c0s1b0", 1); err != nil || cred.Xname != "" {
+		t.Errorf("Expected version 1 not to be kept but got %v, %v", cred, err)
+	}
+
+	if _, err := ccs.RollbackCompCred("x0c0s1b0", 3); err != nil {
+		t.Fatalf("RollbackCompCred failed: %v", err)
+	}
+	if cred, _ := ccs.GetCompCred("x0c0s1b0"); cred.Password != "three" {
+		t.Errorf("Expected the rolled back password but got %q", cred.Password)
+	}
+	if versions, _ := ccs.ListCompCredVersions("x0c0s1b0"); !reflect.DeepEqual(versionNumbers(versions), []int{3, 4, 5}) {
+		t.Errorf("Expected versions 3 to 5 after the rollback but got %v", versions)
+	}
+
+	ccs.DeleteCompCred("x0c0s1b0")
+	if versions, err := ccs.ListCompCredVersions("x0c0s1b0"); err != nil || len(versions) != 0 {
+		t.Errorf("Expected no versions after delete but got %v, %v", versions, err)
+	}
+}
@@ -0,0 +1,300 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package vaulttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type kv2Version struct {
+	data      map[string]interface{}
+	created   time.Time
+	deleted   time.Time
+	destroyed bool
+}
+
+type kv2Secret struct {
+	versions       map[int]*kv2Version
+	currentVersion int
+	oldestVersion  int
+	created        time.Time
+	updated        time.Time
+}
+
+// Get the latest version if it is live, or nil.
+func (sec *kv2Secret) current() *kv2Version {
+	if sec == nil {
+		return nil
+	}
+	v := sec.versions[sec.currentVersion]
+	if v == nil || !v.deleted.IsZero() || v.destroyed {
+		return nil
+	}
+	return v
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (v *kv2Version) metadata(version int) map[string]interface{} {
+	return map[string]interface{}{
+		"created_time":  formatTime(v.created),
+		"deletion_time": formatTime(v.deleted),
+		"destroyed":     v.destroyed,
+		"version":       version,
+	}
+}
+
+func (s *Server) serveKV1(w http.ResponseWriter, r *http.Request, method, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch method {
+	case http.MethodGet:
+		data, ok := s.kv1[path]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data":           copyData(data),
+			"lease_duration": 2764800,
+		})
+	case http.MethodPut, http.MethodPost:
+		var data map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.kv1[path] = data
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.kv1, path)
+		w.WriteHeader(http.StatusNoContent)
+	case "LIST":
+		keys := make([]string, 0, len(s.kv1))
+		for k := range s.kv1 {
+			keys = append(keys, k)
+		}
+		klist := listChildren(keys, path)
+		if len(klist) == 0 {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"keys": klist},
+		})
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "unsupported operation")
+	}
+}
+
+func (s *Server) serveKV2(w http.ResponseWriter, r *http.Request, method string, m *mount, key string) {
+	op, key, _ := strings.Cut(key, "/")
+	full := m.path + "/" + key
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case op == "data" && method == http.MethodGet:
+		s.kv2Read(w, r, full)
+	case op == "data" && (method == http.MethodPut || method == http.MethodPost):
+		s.kv2Write(w, r, m, full)
+	case op == "data" && method == http.MethodDelete:
+		if sec := s.kv2[full]; sec != nil {
+			if v := sec.versions[sec.currentVersion]; v != nil && v.deleted.IsZero() {
+				v.deleted = time.Now()
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case op == "metadata" && method == http.MethodGet:
+		s.kv2Metadata(w, m, full)
+	case op == "metadata" && method == "LIST":
+		keys := make([]string, 0, len(s.kv2))
+		for k := range s.kv2 {
+			keys = append(keys, k)
+		}
+		klist := listChildren(keys, full)
+		if len(klist) == 0 {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"keys": klist},
+		})
+	case op == "metadata" && method == http.MethodDelete:
+		delete(s.kv2, full)
+		w.WriteHeader(http.StatusNoContent)
+	case (op == "delete" || op == "undelete" || op == "destroy") &&
+		(method == http.MethodPut || method == http.MethodPost):
+		s.kv2Versions(w, r, op, full)
+	default:
+		writeErrors(w, http.StatusNotFound, "no handler for route '"+m.path+"/"+op+"'")
+	}
+}
+
+func (s *Server) kv2Read(w http.ResponseWriter, r *http.Request, full string) {
+	sec := s.kv2[full]
+	if sec == nil {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+
+	version := sec.currentVersion
+	if vs := r.URL.Query().Get("version"); vs != "" && vs != "0" {
+		n, err := strconv.Atoi(vs)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, "invalid version")
+			return
+		}
+		version = n
+	}
+	v := sec.versions[version]
+	if v == nil {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	if !v.deleted.IsZero() || v.destroyed {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"data": map[string]interface{}{"data": nil, "metadata": v.metadata(version)},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"data":     copyData(v.data),
+			"metadata": v.metadata(version),
+		},
+	})
+}
+
+func (s *Server) kv2Write(w http.ResponseWriter, r *http.Request, m *mount, full string) {
+	var req struct {
+		Data    map[string]interface{} `json:"data"`
+		Options struct {
+			CAS *int `json:"cas"`
+		} `json:"options"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Data == nil {
+		writeErrors(w, http.StatusBadRequest, "no data provided")
+		return
+	}
+
+	sec := s.kv2[full]
+	if sec == nil {
+		sec = &kv2Secret{versions: make(map[int]*kv2Version), oldestVersion: 1, created: time.Now()}
+		s.kv2[full] = sec
+	}
+	if req.Options.CAS != nil && *req.Options.CAS != sec.currentVersion {
+		writeErrors(w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+		return
+	}
+
+	now := time.Now()
+	sec.currentVersion++
+	sec.updated = now
+	v := &kv2Version{data: req.Data, created: now}
+	sec.versions[sec.currentVersion] = v
+	for sec.currentVersion-sec.oldestVersion >= m.maxVersions {
+		delete(sec.versions, sec.oldestVersion)
+		sec.oldestVersion++
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": v.metadata(sec.currentVersion)})
+}
+
+func (s *Server) kv2Metadata(w http.ResponseWriter, m *mount, full string) {
+	sec := s.kv2[full]
+	if sec == nil {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+
+	versions := make(map[string]interface{})
+	nums := make([]int, 0, len(sec.versions))
+	for n := range sec.versions {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	for _, n := range nums {
+		md := sec.versions[n].metadata(n)
+		delete(md, "version")
+		versions[strconv.Itoa(n)] = md
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"created_time":    formatTime(sec.created),
+			"updated_time":    formatTime(sec.updated),
+			"current_version": sec.currentVersion,
+			"oldest_version":  sec.oldestVersion,
+			"max_versions":    m.maxVersions,
+			"versions":        versions,
+		},
+	})
+}
+
+func (s *Server) kv2Versions(w http.ResponseWriter, r *http.Request, op, full string) {
+	var req struct {
+		Versions []int `json:"versions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sec := s.kv2[full]
+	for _, n := range req.Versions {
+		if sec == nil || sec.versions[n] == nil {
+			continue
+		}
+		v := sec.versions[n]
+		switch op {
+		case "delete":
+			if v.deleted.IsZero() {
+				v.deleted = time.Now()
+			}
+		case "undelete":
+			if !v.destroyed {
+				v.deleted = time.Time{}
+			}
+		case "destroy":
+			v.destroyed = true
+			v.data = nil
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
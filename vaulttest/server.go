@@ -0,0 +1,373 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package vaulttest provides a fake Vault server for end-to-end tests of code
+// that talks to Vault through the real hms-securestorage VaultAdapter.
+//
+// The fake implements the KV version 1 and version 2 read, write, list and
+// delete endpoints, token authentication, and the Kubernetes login endpoint
+// the VaultAdapter uses to obtain its token. It is an in-memory stand-in,
+// not a full Vault: policies, leases and most of the sys/ API are absent.
+package vaulttest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	sstorage "github.com/Cray-HPE/hms-securestorage"
+)
+
+const (
+	// The JWT the fake accepts at the Kubernetes login endpoint.
+	DefaultJWT = "vaulttest-jwt"
+
+	// The role written to the role file by NewVaultAdapter.
+	DefaultRole = "vaulttest"
+
+	kubernetesLoginPath = "auth/kubernetes/login"
+)
+
+type mount struct {
+	path        string
+	version     int
+	maxVersions int
+}
+
+// Server is a fake Vault server backed by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// RootToken is always accepted, even after RevokeTokens.
+	RootToken string
+
+	mu     sync.Mutex
+	mounts map[string]*mount
+	tokens map[string]bool
+	jwts   map[string]bool
+	kv1    map[string]map[string]interface{}
+	kv2    map[string]*kv2Secret
+	logins int
+}
+
+// Start a fake Vault server with "secret" mounted as a KV version 1 engine.
+func NewServer() *Server {
+	s := &Server{
+		RootToken: newToken(),
+		mounts:    make(map[string]*mount),
+		tokens:    make(map[string]bool),
+		jwts:      map[string]bool{DefaultJWT: true},
+		kv1:       make(map[string]map[string]interface{}),
+		kv2:       make(map[string]*kv2Secret),
+	}
+	s.Mount("secret", 1)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func newToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return "s." + hex.EncodeToString(buf)
+}
+
+// Mount a KV secrets engine of the given version (1 or 2) at path, replacing
+// any engine already mounted there.
+func (s *Server) Mount(path string, version int) {
+	path = strings.Trim(path, "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mounts[path] = &mount{path: path, version: version, maxVersions: 10}
+}
+
+// Set the number of versions a KV version 2 mount keeps for each secret.
+func (s *Server) SetMaxVersions(path string, maxVersions int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m, ok := s.mounts[strings.Trim(path, "/")]; ok {
+		m.maxVersions = maxVersions
+	}
+}
+
+// Create a token the server will accept.
+func (s *Server) NewToken() string {
+	token := newToken()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = true
+	return token
+}
+
+// Revoke every token except the root token. Clients holding a revoked token
+// get 403 responses until they log in again.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]bool)
+}
+
+// Get the number of successful Kubernetes logins.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Get a copy of the raw data stored at a KV version 1 path, or of the latest
+// live version at a KV version 2 path (given without the "data/" segment).
+func (s *Server) Data(path string) (map[string]interface{}, bool) {
+	path = strings.Trim(path, "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, key := s.findMount(path)
+	if m == nil {
+		return nil, false
+	}
+	var data map[string]interface{}
+	if m.version == 2 {
+		v := s.kv2[m.path+"/"+key].current()
+		if v == nil {
+			return nil, false
+		}
+		data = v.data
+	} else {
+		var ok bool
+		if data, ok = s.kv1[path]; !ok {
+			return nil, false
+		}
+	}
+	return copyData(data), true
+}
+
+// Create a VaultAdapter connected to the server through its normal
+// Kubernetes login path. The JWT and role files are written to a temporary
+// directory and the Vault environment variables are set for the duration of
+// the test.
+func (s *Server) NewVaultAdapter(t testing.TB, basePath string) sstorage.SecureStorage {
+	t.Helper()
+
+	dir := t.TempDir()
+	jwtFile := filepath.Join(dir, "token")
+	roleFile := filepath.Join(dir, "namespace")
+	if err := os.WriteFile(jwtFile, []byte(DefaultJWT), 0600); err != nil {
+		t.Fatalf("Unable to write JWT file: %v", err)
+	}
+	if err := os.WriteFile(roleFile, []byte(DefaultRole), 0600); err != nil {
+		t.Fatalf("Unable to write role file: %v", err)
+	}
+
+	t.Setenv("VAULT_ADDR", s.URL)
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv(sstorage.EnvVaultJWTFile, jwtFile)
+	t.Setenv(sstorage.EnvVaultRoleFile, roleFile)
+	t.Setenv(sstorage.EnvVaultAuthPath, kubernetesLoginPath)
+
+	ss, err := sstorage.NewVaultAdapter(basePath)
+	if err != nil {
+		t.Fatalf("Unable to create VaultAdapter: %v", err)
+	}
+	return ss
+}
+
+// Find the mount for a path and the key relative to it. The caller must hold
+// the lock.
+func (s *Server) findMount(path string) (*mount, string) {
+	var best *mount
+	for p, m := range s.mounts {
+		if (path == p || strings.HasPrefix(path, p+"/")) && (best == nil || len(p) > len(best.path)) {
+			best = m
+		}
+	}
+	if best == nil {
+		return nil, ""
+	}
+	return best, strings.Trim(strings.TrimPrefix(path, best.path), "/")
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.Header.Get("X-Vault-Token")
+	if token == "" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return token == s.RootToken || s.tokens[token]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		writeErrors(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+
+	method := r.Method
+	if method == http.MethodGet && r.URL.Query().Get("list") == "true" {
+		method = "LIST"
+	}
+
+	if path == kubernetesLoginPath {
+		s.kubernetesLogin(w, r)
+		return
+	}
+
+	if !s.authorized(r) {
+		if r.Header.Get("X-Vault-Token") == "" {
+			writeErrors(w, http.StatusForbidden, "missing client token")
+		} else {
+			writeErrors(w, http.StatusForbidden, "permission denied")
+		}
+		return
+	}
+
+	switch {
+	case path == "auth/token/lookup-self":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"id": r.Header.Get("X-Vault-Token")},
+		})
+	case strings.HasPrefix(path, "sys/internal/ui/mounts/"):
+		s.mountInfo(w, strings.TrimPrefix(path, "sys/internal/ui/mounts/"))
+	default:
+		s.mu.Lock()
+		m, key := s.findMount(path)
+		s.mu.Unlock()
+		if m == nil {
+			writeErrors(w, http.StatusNotFound, "no handler for route '"+path+"'")
+			return
+		}
+		if m.version == 2 {
+			s.serveKV2(w, r, method, m, key)
+		} else {
+			s.serveKV1(w, r, method, path)
+		}
+	}
+}
+
+func (s *Server) kubernetesLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Role string `json:"role"`
+		JWT  string `json:"jwt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	ok := s.jwts[req.JWT] && req.Role != ""
+	s.mu.Unlock()
+	if !ok {
+		writeErrors(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	token := s.NewToken()
+	s.mu.Lock()
+	s.logins++
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"policies":       []string{"default"},
+			"lease_duration": 3600,
+			"renewable":      true,
+		},
+	})
+}
+
+func (s *Server) mountInfo(w http.ResponseWriter, path string) {
+	s.mu.Lock()
+	m, _ := s.findMount(strings.Trim(path, "/"))
+	s.mu.Unlock()
+	if m == nil {
+		writeErrors(w, http.StatusBadRequest, "no mount found for path")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"path":    m.path + "/",
+			"type":    "kv",
+			"options": map[string]interface{}{"version": strconv.Itoa(m.version)},
+		},
+	})
+}
+
+// List the immediate children of prefix among keys, Vault style: sub-paths
+// carry a trailing "/".
+func listChildren(keys []string, prefix string) []string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	seen := make(map[string]bool)
+	klist := []string{}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		child := strings.TrimPrefix(key, prefix)
+		if i := strings.Index(child, "/"); i >= 0 {
+			child = child[:i+1]
+		}
+		if child != "" && !seen[child] {
+			seen[child] = true
+			klist = append(klist, child)
+		}
+	}
+	sort.Strings(klist)
+	return klist
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...string) {
+	if errs == nil {
+		errs = []string{}
+	}
+	writeJSON(w, status, map[string]interface{}{"errors": errs})
+}
@@ -0,0 +1,261 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package vaulttest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func newClient(t *testing.T, s *Server, token string) *api.Client {
+	t.Helper()
+	config := api.DefaultConfig()
+	config.Address = s.URL
+	config.MaxRetries = 0
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatalf("Unable to create Vault client: %v", err)
+	}
+	client.SetToken(token)
+	return client
+}
+
+func TestKV1(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	logical := newClient(t, s, s.RootToken).Logical()
+
+	if _, err := logical.Write("secret/hms-creds/x0c0s1b0", map[string]interface{}{"Username": "root"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := logical.Write("secret/hms-creds/sub/x0c0s2b0", map[string]interface{}{"Username": "admin"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	secret, err := logical.Read("secret/hms-creds/x0c0s1b0")
+	if err != nil || secret == nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if secret.Data["Username"] != "root" {
+		t.Errorf("Expected Username root but got %v", secret.Data["Username"])
+	}
+
+	secret, err = logical.Read("secret/hms-creds/x9999c0s0b0")
+	if err != nil || secret != nil {
+		t.Errorf("Expected nil secret and no error for a missing key but got %v, %v", secret, err)
+	}
+
+	secret, err = logical.List("secret/hms-creds")
+	if err != nil || secret == nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	keys, _ := json.Marshal(secret.Data["keys"])
+	if string(keys) != `["sub/","x0c0s1b0"]` {
+		t.Errorf("Expected keys [sub/ x0c0s1b0] but got %s", keys)
+	}
+
+	secret, err = logical.List("secret/empty")
+	if err != nil || secret != nil {
+		t.Errorf("Expected nil secret and no error listing an empty path but got %v, %v", secret, err)
+	}
+
+	if _, err := logical.Delete("secret/hms-creds/x0c0s1b0"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := s.Data("secret/hms-creds/x0c0s1b0"); ok {
+		t.Errorf("Expected data to be gone after Delete")
+	}
+}
+
+func TestKV2(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Mount("kv", 2)
+	s.SetMaxVersions("kv", 2)
+	logical := newClient(t, s, s.RootToken).Logical()
+
+	for _, user := range []string{"one", "two", "three"} {
+		_, err := logical.Write("kv/data/hms-creds/x0c0s1b0", map[string]interface{}{
+			"data": map[string]interface{}{"Username": user},
+		})
+		if err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	_, err := logical.Write("kv/data/hms-creds/x0c0s1b0", map[string]interface{}{
+		"data":    map[string]interface{}{"Username": "four"},
+		"options": map[string]interface{}{"cas": 1},
+	})
+	if err == nil || !strings.Contains(err.Error(), "check-and-set") {
+		t.Errorf("Expected a check-and-set error but got %v", err)
+	}
+
+	var tests = []struct {
+		version string
+		user    interface{}
+	}{
+		{"", "three"},
+		{"2", "two"},
+		{"1", nil},
+	}
+	for i, test := range tests {
+		var params map[string][]string
+		if test.version != "" {
+			params = map[string][]string{"version": {test.version}}
+		}
+		secret, err := logical.ReadWithData("kv/data/hms-creds/x0c0s1b0", params)
+		if err != nil {
+			t.Fatalf("Test %v Failed: Read error - %v", i, err)
+		}
+		var user interface{}
+		if secret != nil {
+			if data, ok := secret.Data["data"].(map[string]interface{}); ok {
+				user = data["Username"]
+			}
+		}
+		if user != test.user {
+			t.Errorf("Test %v Failed: Expected Username %v but got %v", i, test.user, user)
+		}
+	}
+
+	secret, err := logical.Read("kv/metadata/hms-creds/x0c0s1b0")
+	if err != nil || secret == nil {
+		t.Fatalf("Metadata read failed: %v", err)
+	}
+	versions, _ := secret.Data["versions"].(map[string]interface{})
+	keys := make([]string, 0)
+	for k := range versions {
+		keys = append(keys, k)
+	}
+	if len(keys) != 2 || versions["2"] == nil || versions["3"] == nil {
+		t.Errorf("Expected versions 2 and 3 to be kept but got %v", keys)
+	}
+
+	if _, err := logical.Delete("kv/data/hms-creds/x0c0s1b0"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := s.Data("kv/hms-creds/x0c0s1b0"); ok {
+		t.Errorf("Expected latest version to be deleted")
+	}
+	if _, err := logical.Write("kv/undelete/hms-creds/x0c0s1b0", map[string]interface{}{"versions": []int{3}}); err != nil {
+		t.Fatalf("Undelete failed: %v", err)
+	}
+	data, ok := s.Data("kv/hms-creds/x0c0s1b0")
+	if !ok || data["Username"] != "three" {
+		t.Errorf("Expected version 3 to be restored but got %v", data)
+	}
+
+	secret, err = logical.List("kv/metadata/hms-creds")
+	if err != nil || secret == nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !reflect.DeepEqual(secret.Data["keys"], []interface{}{"x0c0s1b0"}) {
+		t.Errorf("Expected keys [x0c0s1b0] but got %v", secret.Data["keys"])
+	}
+
+	secret, err = logical.Read("sys/internal/ui/mounts/kv/hms-creds")
+	if err != nil || secret == nil {
+		t.Fatalf("Mount info read failed: %v", err)
+	}
+	options, _ := secret.Data["options"].(map[string]interface{})
+	if options["version"] != "2" {
+		t.Errorf("Expected mount version 2 but got %v", options["version"])
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var tests = []struct {
+		token   string
+		respErr bool
+	}{
+		{"", true},
+		{"s.bogus", true},
+		{s.RootToken, false},
+		{s.NewToken(), false},
+	}
+	for i, test := range tests {
+		_, err := newClient(t, s, test.token).Logical().Read("secret/hms-creds/x0c0s1b0")
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+		}
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "code: 403") {
+			t.Errorf("Test %v Failed: Expected a 403 but got %v", i, err)
+		}
+	}
+
+	token := s.NewToken()
+	s.RevokeTokens()
+	if _, err := newClient(t, s, token).Logical().Read("secret/x"); err == nil {
+		t.Errorf("Expected a revoked token to be rejected")
+	}
+	if _, err := newClient(t, s, s.RootToken).Logical().Read("secret/x"); err != nil {
+		t.Errorf("Expected the root token to survive RevokeTokens but got %v", err)
+	}
+}
+
+func TestKubernetesLogin(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	client := newClient(t, s, "")
+	var tests = []struct {
+		jwt     string
+		role    string
+		respErr bool
+	}{
+		{DefaultJWT, DefaultRole, false},
+		{"wrong", DefaultRole, true},
+		{DefaultJWT, "", true},
+	}
+	for i, test := range tests {
+		secret, err := client.Logical().Write(kubernetesLoginPath, map[string]interface{}{
+			"jwt":  test.jwt,
+			"role": test.role,
+		})
+		if (err != nil) != test.respErr {
+			t.Errorf("Test %v Failed: Expected error %v but got %v", i, test.respErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		token, err := secret.TokenID()
+		if err != nil || token == "" {
+			t.Errorf("Test %v Failed: Expected a client token but got %q, %v", i, token, err)
+		}
+		if _, err := newClient(t, s, token).Logical().Read("secret/x"); err != nil {
+			t.Errorf("Test %v Failed: Expected the issued token to work but got %v", i, err)
+		}
+	}
+	if s.Logins() != 1 {
+		t.Errorf("Expected 1 login but got %v", s.Logins())
+	}
+}
@@ -0,0 +1,329 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// The component could not decrypt the request, or its response could not
+// be decrypted, so the stored SNMP privacy password is wrong.
+const StatusPrivFailed Status = "priv_failed"
+
+// The OID SNMPScanner reads, SNMPv2-MIB::sysDescr.0.
+const SysDescrOID = ".1.3.6.1.2.1.1.1.0"
+
+var (
+	authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+		"MD5":    gosnmp.MD5,
+		"SHA":    gosnmp.SHA,
+		"SHA224": gosnmp.SHA224,
+		"SHA256": gosnmp.SHA256,
+		"SHA384": gosnmp.SHA384,
+		"SHA512": gosnmp.SHA512,
+	}
+	privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+		"DES":     gosnmp.DES,
+		"AES":     gosnmp.AES,
+		"AES192":  gosnmp.AES192,
+		"AES256":  gosnmp.AES256,
+		"AES192C": gosnmp.AES192C,
+		"AES256C": gosnmp.AES256C,
+	}
+)
+
+// SNMPScanner performs an SNMPv3 GET of sysDescr on each component, such as
+// a management switch, as the stored Username with the stored SNMPAuthPass
+// and, if set, SNMPPrivPass.
+type SNMPScanner struct {
+	Store *cc.CompCredStore
+
+	// The authentication protocol: MD5, SHA, SHA224, SHA256, SHA384 or
+	// SHA512. Defaults to SHA.
+	AuthProtocol string
+
+	// The privacy protocol: DES, AES, AES192, AES256, AES192C or AES256C.
+	// Defaults to AES.
+	PrivProtocol string
+
+	// How many components are checked at once. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// The timeout for each request, which is retried Retries times.
+	// Defaults to DefaultTimeout.
+	Timeout time.Duration
+	Retries int
+}
+
+// Create a new SNMPScanner.
+func NewSNMPScanner(ccs *cc.CompCredStore) *SNMPScanner {
+	return &SNMPScanner{Store: ccs}
+}
+
+// Check the given components, or every stored one if xnames is empty.
+func (s *SNMPScanner) Scan(ctx context.Context, xnames []string) (Report, error) {
+	authName, privName := s.AuthProtocol, s.PrivProtocol
+	if authName == "" {
+		authName = "SHA"
+	}
+	if privName == "" {
+		privName = "AES"
+	}
+	auth, ok := authProtocols[strings.ToUpper(authName)]
+	if !ok {
+		return Report{}, fmt.Errorf("unknown SNMP authentication protocol %s", authName)
+	}
+	priv, ok := privProtocols[strings.ToUpper(privName)]
+	if !ok {
+		return Report{}, fmt.Errorf("unknown SNMP privacy protocol %s", privName)
+	}
+	return scan(ctx, s.Store, xnames, s.Concurrency,
+		func(ctx context.Context, xname string, cred cc.CompCredentials) Result {
+			return s.check(ctx, auth, priv, xname, cred)
+		})
+}
+
+// Get the address to send SNMP requests to from a credential's URL, which
+// may be a bare host, a host and port, or a URL such as "udp://10.254.0.2".
+func SNMPAddr(cred cc.CompCredentials) string {
+	raw := cred.URL
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "udp://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	port := "161"
+	if u.Scheme == "udp" && u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func (s *SNMPScanner) check(ctx context.Context, auth gosnmp.SnmpV3AuthProtocol, priv gosnmp.SnmpV3PrivProtocol,
+	xname string, cred cc.CompCredentials) Result {
+	res := Result{Xname: xname, URL: SNMPAddr(cred)}
+	if res.URL == "" {
+		return failed(res, StatusError, fmt.Errorf("no URL stored"))
+	}
+	if cred.Username == "" || cred.SNMPAuthPass == "" {
+		return failed(res, StatusError, fmt.Errorf("no SNMP username or authentication password stored"))
+	}
+
+	usm := &gosnmp.UsmSecurityParameters{
+		UserName:                 cred.Username,
+		AuthenticationProtocol:   auth,
+		AuthenticationPassphrase: cred.SNMPAuthPass,
+		Logger:                   gosnmp.NewLogger(nil),
+	}
+	flags := gosnmp.AuthNoPriv
+	if cred.SNMPPrivPass != "" {
+		flags = gosnmp.AuthPriv
+		usm.PrivacyProtocol = priv
+		usm.PrivacyPassphrase = cred.SNMPPrivPass
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", res.URL)
+	if err != nil {
+		return failed(res, classify(err), err)
+	}
+	defer conn.Close()
+	ex := &snmpExchange{conn: conn, timeout: timeout, retries: s.Retries, usm: usm}
+
+	// gosnmp's client discards the unauthenticated reports agents send for
+	// wrong digests and decryption errors, so the exchange is done here,
+	// where the report can be read: discover the agent's engine ID, boots
+	// and time (RFC 3414 section 4), then send the GET.
+	header, _, err := ex.roundTrip(ctx, gosnmp.NoAuthNoPriv, &gosnmp.UsmSecurityParameters{Logger: usm.Logger}, nil)
+	if err != nil {
+		return failed(res, classify(err), err)
+	}
+	ex.sync(header)
+
+	// An agent whose clock has moved on reports notInTimeWindows with its
+	// current time, after which the request is sent once more.
+	for attempt := 0; attempt < 2; attempt++ {
+		header, raw, err := ex.roundTrip(ctx, flags, usm, []gosnmp.SnmpPDU{{Name: SysDescrOID, Type: gosnmp.Null}})
+		if err != nil {
+			return failed(res, classify(err), err)
+		}
+		if header.MsgFlags&gosnmp.AuthNoPriv == 0 {
+			if header.PDUType != gosnmp.Report || len(header.Variables) == 0 {
+				return failed(res, StatusError, fmt.Errorf("unauthenticated %s response", header.PDUType))
+			}
+			switch oid := header.Variables[0].Name; oid {
+			case usmStatsNotInTimeWindows:
+				ex.sync(header)
+				continue
+			case usmStatsWrongDigests:
+				return failed(res, StatusAuthFailed, gosnmp.ErrWrongDigest)
+			case usmStatsUnknownUserNames:
+				return failed(res, StatusAuthFailed, gosnmp.ErrUnknownUsername)
+			case usmStatsDecryptionErrors:
+				return failed(res, StatusPrivFailed, gosnmp.ErrDecryption)
+			case usmStatsUnsupportedSecLevels:
+				return failed(res, StatusError, gosnmp.ErrUnknownSecurityLevel)
+			default:
+				return failed(res, StatusError, fmt.Errorf("SNMP report %s", oid))
+			}
+		}
+
+		// Only an agent with the same keys can have authenticated the
+		// response, so one that fails to verify or decrypt means the
+		// stored passwords do not match.
+		resp, err := ex.decoder(header.MsgFlags&^gosnmp.Reportable).UnmarshalTrap(raw, false)
+		switch {
+		case err != nil && flags == gosnmp.AuthPriv && strings.Contains(err.Error(), "decrypt"):
+			return failed(res, StatusPrivFailed, err)
+		case err != nil:
+			return failed(res, StatusAuthFailed, err)
+		case resp.Error != gosnmp.NoError:
+			return failed(res, StatusError, fmt.Errorf("SNMP error %s", resp.Error))
+		}
+		res.Status = StatusOK
+		return res
+	}
+	return failed(res, StatusError, gosnmp.ErrNotInTimeWindow)
+}
+
+// The USM statistics agents report errors with (RFC 3414 section 5).
+const (
+	usmStatsUnsupportedSecLevels = ".1.3.6.1.6.3.15.1.1.1.0"
+	usmStatsNotInTimeWindows     = ".1.3.6.1.6.3.15.1.1.2.0"
+	usmStatsUnknownUserNames     = ".1.3.6.1.6.3.15.1.1.3.0"
+	usmStatsWrongDigests         = ".1.3.6.1.6.3.15.1.1.5.0"
+	usmStatsDecryptionErrors     = ".1.3.6.1.6.3.15.1.1.6.0"
+)
+
+// One SNMPv3 conversation with an agent over a connected UDP socket.
+type snmpExchange struct {
+	conn    net.Conn
+	timeout time.Duration
+	retries int
+	usm     *gosnmp.UsmSecurityParameters
+}
+
+// Copy the agent's engine ID, boots and time from a report.
+func (ex *snmpExchange) sync(header *gosnmp.SnmpPacket) {
+	if sp, ok := header.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok {
+		ex.usm.AuthoritativeEngineID = sp.AuthoritativeEngineID
+		ex.usm.AuthoritativeEngineBoots = sp.AuthoritativeEngineBoots
+		ex.usm.AuthoritativeEngineTime = sp.AuthoritativeEngineTime
+	}
+}
+
+func (ex *snmpExchange) decoder(flags gosnmp.SnmpV3MsgFlags) *gosnmp.GoSNMP {
+	return &gosnmp.GoSNMP{
+		Version:            gosnmp.Version3,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		MsgFlags:           flags,
+		SecurityParameters: ex.usm.Copy(),
+		Logger:             ex.usm.Logger,
+	}
+}
+
+// Send a GET and wait for the reply with the same message ID, retrying on
+// timeouts. The returned packet is decoded only as far as it can be without
+// keys: the header, and the PDU if it is not encrypted. The raw reply is
+// returned for a full decode.
+func (ex *snmpExchange) roundTrip(ctx context.Context, flags gosnmp.SnmpV3MsgFlags,
+	usm *gosnmp.UsmSecurityParameters, vars []gosnmp.SnmpPDU) (*gosnmp.SnmpPacket, []byte, error) {
+	sp := usm.Copy().(*gosnmp.UsmSecurityParameters)
+	if flags&gosnmp.AuthNoPriv != 0 {
+		if err := sp.InitSecurityKeys(); err != nil {
+			return nil, nil, err
+		}
+	}
+	req := &gosnmp.SnmpPacket{
+		Version:            gosnmp.Version3,
+		MsgFlags:           flags | gosnmp.Reportable,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		SecurityParameters: sp,
+		ContextEngineID:    sp.AuthoritativeEngineID,
+		PDUType:            gosnmp.GetRequest,
+		MsgID:              rand.Uint32() & 0x7fffffff,
+		RequestID:          rand.Uint32() & 0x7fffffff,
+		MsgMaxSize:         65507,
+		Variables:          vars,
+		Logger:             usm.Logger,
+	}
+	if flags&gosnmp.AuthPriv == gosnmp.AuthPriv {
+		if err := sp.InitPacket(req); err != nil {
+			return nil, nil, err
+		}
+	}
+	msg, err := req.MarshalMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buf := make([]byte, 65535)
+	for try := 0; try <= ex.retries; try++ {
+		if _, err := ex.conn.Write(msg); err != nil {
+			return nil, nil, err
+		}
+		deadline := time.Now().Add(ex.timeout)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		ex.conn.SetReadDeadline(deadline)
+		for {
+			n, err := ex.conn.Read(buf)
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				break
+			} else if err != nil {
+				return nil, nil, err
+			}
+			raw := append([]byte(nil), buf[:n]...)
+			header, _ := ex.decoder(gosnmp.NoAuthNoPriv).SnmpDecodePacket(append([]byte(nil), raw...))
+			if header != nil && header.Version == gosnmp.Version3 && header.MsgID == req.MsgID {
+				return header, raw, nil
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nil, nil, &net.OpError{Op: "read", Net: "udp", Addr: ex.conn.RemoteAddr(), Err: os.ErrDeadlineExceeded}
+}
@@ -0,0 +1,252 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package verify
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+const usmStatsUnknownEngineIDs = ".1.3.6.1.6.3.15.1.1.4.0"
+
+// A minimal SNMPv3 agent with one USM user (SHA and AES) that answers GETs
+// of sysDescr, and reports unknown engine IDs, unknown users, wrong digests
+// and decryption errors as a real agent does.
+type snmpAgent struct {
+	conn     net.PacketConn
+	engineID string
+	start    time.Time
+	user     string
+	authPass string
+	privPass string
+}
+
+func newSNMPAgent(t *testing.T, user, authPass, privPass string) *snmpAgent {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	a := &snmpAgent{
+		conn:     conn,
+		engineID: "\x80\x00\x1f\x88\x80testagent",
+		start:    time.Now(),
+		user:     user,
+		authPass: authPass,
+		privPass: privPass,
+	}
+	go a.serve()
+	return a
+}
+
+func (a *snmpAgent) addr() string {
+	return a.conn.LocalAddr().String()
+}
+
+// The agent's own security parameters, with its engine ID and clock.
+func (a *snmpAgent) usm(user string) *gosnmp.UsmSecurityParameters {
+	return &gosnmp.UsmSecurityParameters{
+		UserName:                 user,
+		AuthoritativeEngineID:    a.engineID,
+		AuthoritativeEngineBoots: 1,
+		AuthoritativeEngineTime:  uint32(time.Since(a.start).Seconds()) + 1,
+		AuthenticationProtocol:   gosnmp.SHA,
+		AuthenticationPassphrase: a.authPass,
+		PrivacyProtocol:          gosnmp.AES,
+		PrivacyPassphrase:        a.privPass,
+		Logger:                   gosnmp.NewLogger(nil),
+	}
+}
+
+func (a *snmpAgent) decoder(flags gosnmp.SnmpV3MsgFlags) *gosnmp.GoSNMP {
+	return &gosnmp.GoSNMP{
+		Version:            gosnmp.Version3,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		MsgFlags:           flags,
+		SecurityParameters: a.usm(a.user),
+		Logger:             gosnmp.NewLogger(nil),
+	}
+}
+
+func (a *snmpAgent) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, from, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := a.handle(buf[:n]); resp != nil {
+			a.conn.WriteTo(resp, from)
+		}
+	}
+}
+
+func (a *snmpAgent) handle(msg []byte) []byte {
+	// Read the header, which is never encrypted.
+	header, _ := a.decoder(gosnmp.NoAuthNoPriv).SnmpDecodePacket(append([]byte(nil), msg...))
+	if header == nil || header.Version != gosnmp.Version3 {
+		return nil
+	}
+	sp, ok := header.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case sp.AuthoritativeEngineID != a.engineID:
+		return a.report(header, usmStatsUnknownEngineIDs)
+	case sp.UserName != a.user:
+		return a.report(header, usmStatsUnknownUserNames)
+	}
+
+	flags := header.MsgFlags &^ gosnmp.Reportable
+	d := a.decoder(flags)
+	if err := d.SecurityParameters.InitSecurityKeys(); err != nil {
+		return nil
+	}
+	if flags&gosnmp.AuthNoPriv != 0 {
+		noPriv := a.decoder(gosnmp.AuthNoPriv)
+		noPriv.SecurityParameters.InitSecurityKeys()
+		if _, err := noPriv.UnmarshalTrap(append([]byte(nil), msg...), false); err != nil && err.Error() == "incoming packet is not authentic, discarding" {
+			return a.report(header, usmStatsWrongDigests)
+		}
+	}
+	req, err := d.UnmarshalTrap(append([]byte(nil), msg...), false)
+	if err != nil || req.PDUType != gosnmp.GetRequest {
+		return a.report(header, usmStatsDecryptionErrors)
+	}
+
+	resp := &gosnmp.SnmpPacket{
+		Version:            gosnmp.Version3,
+		MsgFlags:           flags,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		SecurityParameters: a.usm(a.user),
+		ContextEngineID:    a.engineID,
+		PDUType:            gosnmp.GetResponse,
+		MsgID:              header.MsgID,
+		RequestID:          req.RequestID,
+		MsgMaxSize:         65507,
+		Logger:             gosnmp.NewLogger(nil),
+	}
+	for _, v := range req.Variables {
+		if v.Name == SysDescrOID {
+			resp.Variables = append(resp.Variables, gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.OctetString, Value: "Test switch"})
+		} else {
+			resp.Variables = append(resp.Variables, gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.NoSuchObject})
+		}
+	}
+	resp.SecurityParameters.InitSecurityKeys()
+	if flags&gosnmp.AuthPriv == gosnmp.AuthPriv {
+		if err := resp.SecurityParameters.InitPacket(resp); err != nil {
+			return nil
+		}
+	}
+	out, err := resp.MarshalMsg()
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// Build an unauthenticated report of a USM error.
+func (a *snmpAgent) report(req *gosnmp.SnmpPacket, oid string) []byte {
+	resp := &gosnmp.SnmpPacket{
+		Version:            gosnmp.Version3,
+		MsgFlags:           gosnmp.NoAuthNoPriv,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		SecurityParameters: a.usm(""),
+		ContextEngineID:    a.engineID,
+		PDUType:            gosnmp.Report,
+		MsgID:              req.MsgID,
+		RequestID:          req.RequestID,
+		MsgMaxSize:         65507,
+		Variables:          []gosnmp.SnmpPDU{{Name: oid, Type: gosnmp.Counter32, Value: uint32(1)}},
+		Logger:             gosnmp.NewLogger(nil),
+	}
+	out, err := resp.MarshalMsg()
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func TestSNMPAddr(t *testing.T) {
+	var tests = []struct {
+		url  string
+		addr string
+	}{
+		{"10.254.0.2", "10.254.0.2:161"},
+		{"udp://10.254.0.2:1161", "10.254.0.2:1161"},
+		{"https://sw-leaf-001/redfish/v1", "sw-leaf-001:161"},
+		{"", ""},
+	}
+	for i, test := range tests {
+		if addr := SNMPAddr(cc.CompCredentials{URL: test.url}); addr != test.addr {
+			t.Errorf("Test %v Failed: Expected address %q but got %q", i, test.addr, addr)
+		}
+	}
+}
+
+func TestSNMPScan(t *testing.T) {
+	agent := newSNMPAgent(t, "testuser", "authpass1", "privpass1")
+	url := "udp://" + agent.addr()
+	silent, _ := net.ListenPacket("udp", "127.0.0.1:0")
+	defer silent.Close()
+
+	ccs := store(t,
+		cc.CompCredentials{Xname: "x3000c0w1", URL: url, Username: "testuser", SNMPAuthPass: "authpass1", SNMPPrivPass: "privpass1"},
+		cc.CompCredentials{Xname: "x3000c0w2", URL: url, Username: "testuser", SNMPAuthPass: "wrongauth", SNMPPrivPass: "privpass1"},
+		cc.CompCredentials{Xname: "x3000c0w3", URL: url, Username: "testuser", SNMPAuthPass: "authpass1", SNMPPrivPass: "wrongpriv"},
+		cc.CompCredentials{Xname: "x3000c0w4", URL: url, Username: "nobody", SNMPAuthPass: "authpass1", SNMPPrivPass: "privpass1"},
+		cc.CompCredentials{Xname: "x3000c0w5", URL: "udp://" + silent.LocalAddr().String(), Username: "testuser", SNMPAuthPass: "authpass1"},
+		cc.CompCredentials{Xname: "x3000c0w6", URL: url, Username: "testuser"},
+	)
+
+	s := NewSNMPScanner(ccs)
+	s.Timeout = 500 * time.Millisecond
+	report, err := s.Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	expected := []Status{StatusOK, StatusAuthFailed, StatusPrivFailed, StatusAuthFailed, StatusUnreachable, StatusError}
+	if len(report.Results) != len(expected) {
+		t.Fatalf("Expected %d results but got %d", len(expected), len(report.Results))
+	}
+	for i, res := range report.Results {
+		if res.Status != expected[i] {
+			t.Errorf("Test %v Failed: Expected %s for %s but got %s (%s)", i, expected[i], res.Xname, res.Status, res.Error)
+		}
+	}
+
+	s.AuthProtocol = "SHA999"
+	if _, err := s.Scan(context.Background(), nil); err == nil {
+		t.Errorf("Expected an error for an unknown authentication protocol")
+	}
+}
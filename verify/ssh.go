@@ -0,0 +1,196 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// The host key a component presented did not match its pinned SSHHostKey.
+const StatusHostKeyMismatch Status = "host_key_mismatch"
+
+var errHostKeyMismatch = errors.New("host key does not match the pinned key")
+
+// SSHScanner logs in over SSH to each component, such as a management
+// switch, with its stored password and/or SSHKey, and checks the host key
+// it presents against its pinned SSHHostKey.
+type SSHScanner struct {
+	Store *cc.CompCredStore
+
+	// How many components are checked at once. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// The timeout for connecting and logging in to each component.
+	// Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// Accept components with no pinned host key instead of reporting them
+	// as errors. Their host key's fingerprint is reported either way.
+	AllowUnpinned bool
+}
+
+// Create a new SSHScanner.
+func NewSSHScanner(ccs *cc.CompCredStore) *SSHScanner {
+	return &SSHScanner{Store: ccs}
+}
+
+// Check the given components, or every stored one if xnames is empty.
+func (s *SSHScanner) Scan(ctx context.Context, xnames []string) (Report, error) {
+	return scan(ctx, s.Store, xnames, s.Concurrency, s.check)
+}
+
+// Get the address to SSH to from a credential's URL, which may be a bare
+// host, a host and port, or a URL such as "ssh://10.254.0.2:22".
+func SSHAddr(cred cc.CompCredentials) string {
+	raw := cred.URL
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "ssh://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func (s *SSHScanner) check(ctx context.Context, xname string, cred cc.CompCredentials) Result {
+	res := Result{Xname: xname, URL: SSHAddr(cred)}
+	if res.URL == "" {
+		return failed(res, StatusError, fmt.Errorf("no URL stored"))
+	}
+
+	var auth []ssh.AuthMethod
+	if cred.SSHKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(cred.SSHKey))
+		if err != nil {
+			return failed(res, StatusError, fmt.Errorf("unable to parse SSH key: %v", err))
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cred.Password != "" {
+		auth = append(auth, ssh.Password(cred.Password))
+	}
+	if len(auth) == 0 {
+		return failed(res, StatusError, fmt.Errorf("no password or SSH key stored"))
+	}
+
+	var pinned []ssh.PublicKey
+	for rest := []byte(cred.SSHHostKey); len(bytes.TrimSpace(rest)) > 0; {
+		key, _, _, next, err := ssh.ParseAuthorizedKey(rest)
+		if err != nil {
+			return failed(res, StatusError, fmt.Errorf("unable to parse pinned host key: %v", err))
+		}
+		pinned = append(pinned, key)
+		rest = next
+	}
+	if len(pinned) == 0 && !s.AllowUnpinned {
+		return failed(res, StatusError, fmt.Errorf("no host key pinned"))
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	config := &ssh.ClientConfig{
+		User:              cred.Username,
+		Auth:              auth,
+		HostKeyAlgorithms: hostKeyAlgorithms(pinned),
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			res.HostKey = ssh.FingerprintSHA256(key)
+			if len(pinned) == 0 {
+				return nil
+			}
+			for _, p := range pinned {
+				if bytes.Equal(p.Marshal(), key.Marshal()) {
+					return nil
+				}
+			}
+			return errHostKeyMismatch
+		},
+		Timeout: timeout,
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", res.URL)
+	if err != nil {
+		return failed(res, classify(err), err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, res.URL, config)
+	switch {
+	case errors.Is(err, errHostKeyMismatch):
+		return failed(res, StatusHostKeyMismatch, err)
+	case err != nil && len(pinned) > 0 && strings.Contains(err.Error(), "no common algorithm for host key"):
+		// The component has no host key of a pinned type.
+		return failed(res, StatusHostKeyMismatch, err)
+	case err != nil && strings.Contains(err.Error(), "unable to authenticate"):
+		return failed(res, StatusAuthFailed, err)
+	case err != nil:
+		return failed(res, classify(err), err)
+	}
+	ssh.NewClient(c, chans, reqs).Close()
+	res.Status = StatusOK
+	return res
+}
+
+// Get the host key algorithms to ask for, so that a component with several
+// host keys presents a pinned one rather than the one the client prefers.
+// Returns nil, for the defaults, if no key is pinned.
+func hostKeyAlgorithms(pinned []ssh.PublicKey) []string {
+	var algos []string
+	seen := make(map[string]bool)
+	for _, key := range pinned {
+		names := []string{key.Type()}
+		if key.Type() == ssh.KeyAlgoRSA {
+			names = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				algos = append(algos, name)
+			}
+		}
+	}
+	return algos
+}
@@ -0,0 +1,214 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package verify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"net"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// An SSH server that accepts one user with a password or a public key. It
+// has an ed25519 host key, and any extra host keys given.
+type sshServer struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+}
+
+func newSSHServer(t *testing.T, user, password string, clientKey ssh.PublicKey, extraHostKeys ...ssh.Signer) *sshServer {
+	t.Helper()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	hostKey, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == user && string(pass) == password {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if c.User() == user && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	config.AddHostKey(hostKey)
+	for _, key := range extraHostKeys {
+		config.AddHostKey(key)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					ch.Reject(ssh.Prohibited, "no sessions")
+				}
+			}()
+		}
+	}()
+	return &sshServer{listener: l, hostKey: hostKey}
+}
+
+func (s *sshServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *sshServer) pin() string {
+	return string(ssh.MarshalAuthorizedKey(s.hostKey.PublicKey()))
+}
+
+func TestSSHAddr(t *testing.T) {
+	var tests = []struct {
+		url  string
+		addr string
+	}{
+		{"10.254.0.2", "10.254.0.2:22"},
+		{"10.254.0.2:2222", "10.254.0.2:2222"},
+		{"ssh://sw-leaf-001:830", "sw-leaf-001:830"},
+		{"https://10.254.0.2/redfish/v1", "10.254.0.2:22"},
+		{"", ""},
+	}
+	for i, test := range tests {
+		if addr := SSHAddr(cc.CompCredentials{URL: test.url}); addr != test.addr {
+			t.Errorf("Test %v Failed: Expected address %q but got %q", i, test.addr, addr)
+		}
+	}
+}
+
+func TestSSHScan(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	clientKey, _ := ssh.NewPublicKey(pub)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := string(pem.EncodeToMemory(block))
+
+	srv := newSSHServer(t, "admin", "switchpass", clientKey)
+	other := newSSHServer(t, "admin", "switchpass", clientKey)
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+
+	ccs := store(t,
+		cc.CompCredentials{Xname: "x3000c0w1", URL: srv.addr(), Username: "admin", Password: "switchpass", SSHHostKey: srv.pin()},
+		cc.CompCredentials{Xname: "x3000c0w2", URL: srv.addr(), Username: "admin", Password: "stale", SSHHostKey: srv.pin()},
+		cc.CompCredentials{Xname: "x3000c0w3", URL: "ssh://" + srv.addr(), Username: "admin", SSHKey: keyPEM, SSHHostKey: srv.pin()},
+		cc.CompCredentials{Xname: "x3000c0w4", URL: srv.addr(), Username: "admin", Password: "switchpass", SSHHostKey: other.pin()},
+		cc.CompCredentials{Xname: "x3000c0w5", URL: srv.addr(), Username: "admin", Password: "switchpass"},
+		cc.CompCredentials{Xname: "x3000c0w6", URL: closed.Addr().String(), Username: "admin", Password: "switchpass", SSHHostKey: srv.pin()},
+		cc.CompCredentials{Xname: "x3000c0w7", URL: srv.addr(), Username: "admin", SSHKey: "not a key", SSHHostKey: srv.pin()},
+		cc.CompCredentials{Xname: "x3000c0w8", URL: srv.addr(), Username: "admin", SSHHostKey: srv.pin()},
+	)
+
+	report, err := NewSSHScanner(ccs).Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	expected := []Status{StatusOK, StatusAuthFailed, StatusOK, StatusHostKeyMismatch,
+		StatusError, StatusUnreachable, StatusError, StatusError}
+	if len(report.Results) != len(expected) {
+		t.Fatalf("Expected %d results but got %d", len(expected), len(report.Results))
+	}
+	for i, res := range report.Results {
+		if res.Status != expected[i] {
+			t.Errorf("Test %v Failed: Expected %s for %s but got %s (%s)", i, expected[i], res.Xname, res.Status, res.Error)
+		}
+	}
+	fingerprint := ssh.FingerprintSHA256(srv.hostKey.PublicKey())
+	if report.Results[0].HostKey != fingerprint {
+		t.Errorf("Expected host key %s but got %s", fingerprint, report.Results[0].HostKey)
+	}
+	if !strings.Contains(report.Results[4].Error, "no host key pinned") {
+		t.Errorf("Expected an unpinned host error but got %q", report.Results[4].Error)
+	}
+
+	// Unpinned hosts pass when allowed, reporting the key to pin.
+	s := NewSSHScanner(ccs)
+	s.AllowUnpinned = true
+	report, err = s.Scan(context.Background(), []string{"x3000c0w5"})
+	if err != nil || report.Results[0].Status != StatusOK || report.Results[0].HostKey != fingerprint {
+		t.Errorf("Expected ok with host key %s but got %+v, %v", fingerprint, report.Results, err)
+	}
+}
+
+func TestSSHScanHostKeyAlgorithms(t *testing.T) {
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecdsaSigner, _ := ssh.NewSignerFromKey(ecdsaKey)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaSigner, _ := ssh.NewSignerFromKey(rsaKey)
+	pin := func(s ssh.Signer) string {
+		return string(ssh.MarshalAuthorizedKey(s.PublicKey()))
+	}
+
+	// Clients prefer ECDSA host keys to ed25519 ones, so a switch with
+	// both must be asked for the pinned one.
+	srv := newSSHServer(t, "admin", "switchpass", nil, ecdsaSigner)
+	withRSA := newSSHServer(t, "admin", "switchpass", nil, rsaSigner)
+	ccs := store(t,
+		cc.CompCredentials{Xname: "x3000c0w1", URL: srv.addr(), Username: "admin", Password: "switchpass", SSHHostKey: srv.pin()},
+		cc.CompCredentials{Xname: "x3000c0w2", URL: srv.addr(), Username: "admin", Password: "switchpass", SSHHostKey: pin(ecdsaSigner)},
+		cc.CompCredentials{Xname: "x3000c0w3", URL: withRSA.addr(), Username: "admin", Password: "switchpass", SSHHostKey: pin(rsaSigner)},
+		cc.CompCredentials{Xname: "x3000c0w4", URL: withRSA.addr(), Username: "admin", Password: "switchpass", SSHHostKey: pin(ecdsaSigner)},
+	)
+
+	report, err := NewSSHScanner(ccs).Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	expected := []Status{StatusOK, StatusOK, StatusOK, StatusHostKeyMismatch}
+	for i, res := range report.Results {
+		if res.Status != expected[i] {
+			t.Errorf("Test %v Failed: Expected %s for %s but got %s (%s)", i, expected[i], res.Xname, res.Status, res.Error)
+		}
+	}
+}
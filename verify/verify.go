@@ -0,0 +1,318 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package verify checks that the credentials in a CompCredStore are still
+// accepted by the components they belong to.
+package verify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/redfish"
+)
+
+// Status classifies the outcome of checking one component.
+type Status string
+
+const (
+	StatusOK          Status = "ok"
+	StatusAuthFailed  Status = "auth_failed"
+	StatusUnreachable Status = "unreachable"
+	StatusTLSError    Status = "tls_error"
+
+	// Anything else, such as a missing URL or an unexpected HTTP status.
+	StatusError Status = "error"
+)
+
+const (
+	DefaultConcurrency = 16
+	DefaultTimeout     = 10 * time.Second
+)
+
+// Result is the outcome of checking one component. HostKey is the SHA256
+// fingerprint of the SSH host key a component presented.
+type Result struct {
+	Xname      string        `json:"xname"`
+	URL        string        `json:"url"`
+	Status     Status        `json:"status"`
+	HTTPStatus int           `json:"http_status,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	HostKey    string        `json:"host_key,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Report holds the results of a scan, sorted by xname.
+type Report struct {
+	Started  time.Time      `json:"started"`
+	Duration time.Duration  `json:"duration"`
+	Results  []Result       `json:"results"`
+	Summary  map[Status]int `json:"summary"`
+}
+
+// Check whether every component accepted its credentials.
+func (r Report) OK() bool {
+	return r.Summary[StatusOK] == len(r.Results)
+}
+
+// Get the results that are not StatusOK.
+func (r Report) Failures() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if res.Status != StatusOK {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Scanner makes an authenticated GET against the stored URL of each
+// component and classifies the response. The Redfish service root needs no
+// authentication, so a stored URL pointing at it is checked against the
+// SessionService instead.
+type Scanner struct {
+	Store *cc.CompCredStore
+
+	// If set, requests go to this path on each component, such as
+	// "/redfish/v1/Managers", instead of to the stored URL.
+	Path string
+
+	// The client requests are made with. Its TLS configuration decides
+	// which BMC certificates are trusted. Defaults to a client with
+	// DefaultTimeout.
+	Client *http.Client
+
+	// How many components are checked at once. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// Requests per second allowed to each cabinet, with bursts of
+	// CabinetBurst. Zero means no limit.
+	CabinetRate  rate.Limit
+	CabinetBurst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// Create a new Scanner.
+func NewScanner(ccs *cc.CompCredStore) *Scanner {
+	return &Scanner{Store: ccs}
+}
+
+var cabinetRE = regexp.MustCompile(`^x\d+`)
+
+// Get the cabinet an xname is in, e.g. "x1000" for "x1000c0s1b0". Names
+// that are not xnames share the cabinet "".
+func Cabinet(xname string) string {
+	return cabinetRE.FindString(xname)
+}
+
+// Check the given components, or every stored one if xnames is empty.
+// Components with no stored credentials are reported as StatusError.
+func (s *Scanner) Scan(ctx context.Context, xnames []string) (Report, error) {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return scan(ctx, s.Store, xnames, s.Concurrency,
+		func(ctx context.Context, xname string, cred cc.CompCredentials) Result {
+			return s.check(ctx, client, xname, cred)
+		})
+}
+
+// A check of one component's stored credentials.
+type checkFunc func(ctx context.Context, xname string, cred cc.CompCredentials) Result
+
+// Run a check on the given components, or every stored one if xnames is
+// empty, with up to concurrency checks at once, and report the results.
+// Components with no stored credentials are reported as StatusError without
+// being checked.
+func scan(ctx context.Context, ccs *cc.CompCredStore, xnames []string, concurrency int, check checkFunc) (Report, error) {
+	report := Report{Started: time.Now(), Summary: make(map[Status]int)}
+
+	var creds map[string]cc.CompCredentials
+	var err error
+	if len(xnames) == 0 {
+		creds, err = ccs.GetAllCompCreds()
+		for xname := range creds {
+			xnames = append(xnames, xname)
+		}
+	} else {
+		creds, err = ccs.GetCompCreds(xnames)
+	}
+	if err != nil {
+		return report, err
+	}
+	sort.Strings(xnames)
+
+	workers := concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	report.Results = make([]Result, len(xnames))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(xnames); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				xname := xnames[i]
+				cred, ok := creds[xname]
+				if !ok || cred.Xname == "" {
+					report.Results[i] = Result{Xname: xname, Status: StatusError, Error: "no credentials stored"}
+					continue
+				}
+				start := time.Now()
+				res := check(ctx, xname, cred)
+				res.Duration = time.Since(start)
+				report.Results[i] = res
+			}
+		}()
+	}
+	for i := range xnames {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, res := range report.Results {
+		report.Summary[res.Status]++
+	}
+	report.Duration = time.Since(report.Started)
+	return report, ctx.Err()
+}
+
+func (s *Scanner) limiter(cabinet string) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limiters == nil {
+		s.limiters = make(map[string]*rate.Limiter)
+	}
+	l, ok := s.limiters[cabinet]
+	if !ok {
+		burst := s.CabinetBurst
+		if burst <= 0 {
+			burst = 1
+		}
+		l = rate.NewLimiter(s.CabinetRate, burst)
+		s.limiters[cabinet] = l
+	}
+	return l
+}
+
+// Check one component.
+func (s *Scanner) check(ctx context.Context, client *http.Client, xname string, cred cc.CompCredentials) Result {
+	res := Result{Xname: xname, URL: s.target(cred)}
+	if res.URL == "" {
+		return failed(res, StatusError, fmt.Errorf("no URL stored"))
+	}
+
+	if s.CabinetRate > 0 {
+		if err := s.limiter(Cabinet(xname)).Wait(ctx); err != nil {
+			return failed(res, StatusError, err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+	if err != nil {
+		return failed(res, StatusError, err)
+	}
+	req.SetBasicAuth(cred.Username, cred.Password)
+	resp, err := client.Do(req)
+	if err != nil {
+		return failed(res, classify(err), err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	res.HTTPStatus = resp.StatusCode
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		res.Status = StatusOK
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		res.Status = StatusAuthFailed
+	default:
+		return failed(res, StatusError, fmt.Errorf("unexpected status %s", resp.Status))
+	}
+	return res
+}
+
+// Get the URL to check a component with.
+func (s *Scanner) target(cred cc.CompCredentials) string {
+	base := redfish.CredBaseURL(cred)
+	if s.Path != "" && base != "" {
+		return base + s.Path
+	}
+	u, err := url.Parse(redfish.CredURL(cred))
+	if err == nil && base != "" && (u.Path == "" || strings.TrimSuffix(u.Path, "/") == "/redfish/v1") {
+		return base + "/redfish/v1/SessionService"
+	}
+	return redfish.CredURL(cred)
+}
+
+func failed(res Result, status Status, err error) Result {
+	res.Status = status
+	res.Error = err.Error()
+	return res
+}
+
+// Classify an error from sending a request.
+func classify(err error) Status {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostname         x509.HostnameError
+		invalid          x509.CertificateInvalidError
+		verification     *tls.CertificateVerificationError
+		recordHeader     tls.RecordHeaderError
+		alert            tls.AlertError
+		opErr            *net.OpError
+		dnsErr           *net.DNSError
+		netErr           net.Error
+	)
+	switch {
+	case errors.As(err, &unknownAuthority), errors.As(err, &hostname),
+		errors.As(err, &invalid), errors.As(err, &verification),
+		errors.As(err, &recordHeader), errors.As(err, &alert):
+		return StatusTLSError
+	case errors.As(err, &opErr), errors.As(err, &dnsErr),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return StatusUnreachable
+	}
+	return StatusError
+}
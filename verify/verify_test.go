@@ -0,0 +1,164 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package verify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cc "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-compcredentials/conformance"
+	"github.com/Cray-HPE/hms-compcredentials/redfishtest"
+)
+
+func store(t *testing.T, creds ...cc.CompCredentials) *cc.CompCredStore {
+	t.Helper()
+	ccs := cc.NewCompCredStore(cc.DefaultCompCredPath, conformance.NewMemoryStorage())
+	for _, cred := range creds {
+		if err := ccs.StoreCompCred(cred); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ccs
+}
+
+func TestCabinet(t *testing.T) {
+	var tests = []struct {
+		xname   string
+		cabinet string
+	}{
+		{"x1000c0s1b0", "x1000"},
+		{"x3000c0r24b0", "x3000"},
+		{"x9", "x9"},
+		{"d0w1", ""},
+	}
+	for i, test := range tests {
+		if cabinet := Cabinet(test.xname); cabinet != test.cabinet {
+			t.Errorf("Test %v Failed: Expected cabinet %q but got %q", i, test.cabinet, cabinet)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	bmc := redfishtest.NewServer()
+	defer bmc.Close()
+	bmc.AddAccount("root", "initial0", "Administrator")
+
+	tlsBMC := redfishtest.NewTLSServer()
+	defer tlsBMC.Close()
+	tlsBMC.AddAccount("root", "initial0", "Administrator")
+
+	gone := redfishtest.NewServer()
+	gone.Close()
+
+	ccs := store(t,
+		cc.CompCredentials{Xname: "x0c0s0b0", URL: bmc.URL + "/redfish/v1/Systems", Username: "root", Password: "initial0"},
+		cc.CompCredentials{Xname: "x0c0s1b0", URL: bmc.URL + "/redfish/v1/Systems", Username: "root", Password: "stale"},
+		cc.CompCredentials{Xname: "x0c0s2b0", URL: gone.URL + "/redfish/v1/Systems", Username: "root", Password: "initial0"},
+		cc.CompCredentials{Xname: "x0c0s3b0", URL: tlsBMC.URL + "/redfish/v1/Systems", Username: "root", Password: "initial0"},
+		cc.CompCredentials{Xname: "x0c0s4b0", URL: bmc.URL + "/redfish/v1/Nonexistent", Username: "root", Password: "initial0"},
+		cc.CompCredentials{Xname: "x0c0s5b0", Username: "root", Password: "initial0"},
+		cc.CompCredentials{Xname: "x0c0s6b0", URL: bmc.URL + "/redfish/v1", Username: "root", Password: "stale"},
+	)
+
+	report, err := NewScanner(ccs).Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := []Status{StatusOK, StatusAuthFailed, StatusUnreachable, StatusTLSError, StatusError, StatusError, StatusAuthFailed}
+	if len(report.Results) != len(expected) {
+		t.Fatalf("Expected %d results but got %d", len(expected), len(report.Results))
+	}
+	for i, res := range report.Results {
+		if res.Status != expected[i] {
+			t.Errorf("Test %v Failed: Expected %s for %s but got %s (%s)", i, expected[i], res.Xname, res.Status, res.Error)
+		}
+	}
+	if report.Results[1].HTTPStatus != 401 {
+		t.Errorf("Expected HTTP status 401 but got %d", report.Results[1].HTTPStatus)
+	}
+	if report.Summary[StatusError] != 2 || report.Summary[StatusOK] != 1 {
+		t.Errorf("Unexpected summary %v", report.Summary)
+	}
+	if report.OK() || len(report.Failures()) != 6 {
+		t.Errorf("Expected 6 failures but got %d", len(report.Failures()))
+	}
+	if url := report.Results[6].URL; url != bmc.URL+"/redfish/v1/SessionService" {
+		t.Errorf("Expected the service root to be checked through the SessionService but got %s", url)
+	}
+
+	// Trusting the TLS BMC's certificate makes it pass.
+	s := NewScanner(ccs)
+	s.Client = tlsBMC.Client()
+	report, err = s.Scan(context.Background(), []string{"x0c0s3b0", "x0c0s9b0"})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if report.Results[0].Status != StatusOK {
+		t.Errorf("Expected ok for a trusted certificate but got %s (%s)", report.Results[0].Status, report.Results[0].Error)
+	}
+	if report.Results[1].Status != StatusError {
+		t.Errorf("Expected error for an xname with no credentials but got %s", report.Results[1].Status)
+	}
+
+	// Path overrides the stored URL.
+	s = NewScanner(ccs)
+	s.Path = "/redfish/v1/Managers"
+	report, err = s.Scan(context.Background(), []string{"x0c0s4b0"})
+	if err != nil || report.Results[0].Status != StatusOK {
+		t.Errorf("Expected ok checking %s but got %v, %v", s.Path, report.Results, err)
+	}
+}
+
+func TestScanCabinetRate(t *testing.T) {
+	bmc := redfishtest.NewServer()
+	defer bmc.Close()
+	bmc.AddAccount("root", "initial0", "Administrator")
+
+	var creds []cc.CompCredentials
+	for _, xname := range []string{"x1000c0s0b0", "x1000c0s1b0", "x1000c0s2b0", "x2000c0s0b0", "x3000c0s0b0"} {
+		creds = append(creds, cc.CompCredentials{Xname: xname, URL: bmc.URL + "/redfish/v1/", Username: "root", Password: "initial0"})
+	}
+	s := NewScanner(store(t, creds...))
+	s.CabinetRate = 10
+
+	report, err := s.Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("Expected every check to pass but got %v", report.Failures())
+	}
+	// Three checks in x1000 at 10/s with no burst take at least 200ms;
+	// the other cabinets are not held up behind them.
+	if report.Duration < 190*time.Millisecond {
+		t.Errorf("Expected the cabinet rate limit to slow the scan, took %v", report.Duration)
+	}
+	for _, res := range report.Results {
+		if Cabinet(res.Xname) != "x1000" && res.Duration > 100*time.Millisecond {
+			t.Errorf("Expected %s not to wait on x1000's limit, took %v", res.Xname, res.Duration)
+		}
+	}
+}